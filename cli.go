@@ -0,0 +1,672 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"wingie_case/executor"
+	"wingie_case/input"
+	"wingie_case/model"
+	"wingie_case/output"
+	"wingie_case/scheduler"
+	"wingie_case/server"
+	"wingie_case/validator"
+)
+
+// Exit codes returned by the command-line interface, one per error class.
+const (
+	exitOK         = 0
+	exitFailure    = 1 // unexpected error (e.g. output file cannot be written)
+	exitUsage      = 2 // bad command line
+	exitInput      = 3 // job could not be read or parsed
+	exitValidation = 4 // job definition is invalid
+	exitCycle      = 5 // dependency graph contains a cycle
+	exitScheduling = 6 // scheduler rejected the job
+	exitExecution  = 7 // a task command failed or the run was interrupted
+)
+
+// stage identifies the pipeline step an error came from.
+type stage int
+
+const (
+	stageInput stage = iota + 1
+	stageValidation
+	stageScheduling
+	stageExecution
+)
+
+// stageError tags an error with the pipeline stage that produced it.
+type stageError struct {
+	stage stage
+	err   error
+}
+
+func (e *stageError) Error() string {
+	switch e.stage {
+	case stageInput:
+		return fmt.Sprintf("input error: %v", e.err)
+	case stageValidation:
+		return fmt.Sprintf("validation error: %v", e.err)
+	case stageScheduling:
+		return fmt.Sprintf("scheduling error: %v", e.err)
+	case stageExecution:
+		return fmt.Sprintf("execution error: %v", e.err)
+	}
+	return e.err.Error()
+}
+
+func (e *stageError) Unwrap() error {
+	return e.err
+}
+
+// exitCode maps an error returned by App to the process exit code.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	// A job that is both malformed and cyclic reports the validation error;
+	// exitCycle means the cycle is the only problem.
+	var valErr *validator.ValidationError
+	if errors.As(err, &valErr) {
+		return exitValidation
+	}
+	var cycleErr *validator.CycleError
+	if errors.As(err, &cycleErr) {
+		return exitCycle
+	}
+	var se *stageError
+	if errors.As(err, &se) {
+		switch se.stage {
+		case stageInput:
+			return exitInput
+		case stageValidation:
+			return exitValidation
+		case stageScheduling:
+			return exitScheduling
+		case stageExecution:
+			return exitExecution
+		}
+	}
+	return exitFailure
+}
+
+// exportFormats lists the printers available to the export command.
+var exportFormats = map[string]func(w io.Writer) output.Printer{
+	"text":  func(w io.Writer) output.Printer { return output.NewConsolePrinterWithWriter(w) },
+	"gantt": func(w io.Writer) output.Printer { return output.NewGanttPrinterWithWriter(w, output.TerminalWidth()) },
+	"json":  func(w io.Writer) output.Printer { return output.NewJSONPrinterWithWriter(w) },
+	"csv":   func(w io.Writer) output.Printer { return output.NewCSVPrinterWithWriter(w) },
+}
+
+// graphFormats lists the dependency-graph exporters; they need the job as
+// well as the result.
+var graphFormats = map[string]func(w io.Writer) output.GraphPrinter{
+	"dot":     func(w io.Writer) output.GraphPrinter { return output.NewDOTPrinterWithWriter(w) },
+	"mermaid": func(w io.Writer) output.GraphPrinter { return output.NewMermaidPrinterWithWriter(w) },
+}
+
+// cliOptions holds the flags shared by the subcommands.
+type cliOptions struct {
+	workers  int
+	output   string
+	quiet    bool
+	gantt    bool
+	format   string
+	priority string
+	optimal  bool
+	maxTasks int
+	timeout  time.Duration
+	addr     string
+	logs     string
+	policy   string
+	state    string
+}
+
+// runCLI dispatches to a subcommand and returns the process exit code.
+// Without arguments it falls back to the interactive prompts.
+func runCLI(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return runInteractive(stdin, stdout, stderr)
+	}
+
+	switch args[0] {
+	case "schedule":
+		return runSchedule(args[1:], stdin, stdout, stderr)
+	case "validate":
+		return runValidate(args[1:], stdin, stdout, stderr)
+	case "export":
+		return runExport(args[1:], stdin, stdout, stderr)
+	case "serve":
+		return runServe(args[1:], stdout, stderr)
+	case "run":
+		return runExecute("run", args[1:], stdin, stdout, stderr)
+	case "resume":
+		return runExecute("resume", args[1:], stdin, stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "unknown command '%s'\n\n", args[0])
+	printUsage(stderr)
+	return exitUsage
+}
+
+func runInteractive(stdin io.Reader, stdout, stderr io.Writer) int {
+	printWelcome(stdout)
+
+	app := NewApp(
+		input.NewCLIReader(stdin),
+		validator.NewGraphValidator(),
+		scheduler.NewWorkerScheduler(),
+		output.NewConsolePrinterWithWriter(stdout),
+	).WithWarnings(stderr)
+	if err := app.Run(); err != nil {
+		fmt.Fprintln(stderr)
+		return reportError(stderr, err)
+	}
+	return exitOK
+}
+
+// runSchedule reads a job file, schedules it and prints the plan.
+// With --quiet only the minimum completion time is printed.
+func runSchedule(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("schedule", stderr)
+	fs.IntVar(&opts.workers, "workers", 0, "override the worker count from the job file")
+	fs.StringVar(&opts.output, "output", "", "write the plan to `file` instead of stdout")
+	fs.BoolVar(&opts.quiet, "quiet", false, "print only the minimum completion time, without warnings")
+	fs.BoolVar(&opts.gantt, "gantt", false, "draw a Gantt chart instead of the table")
+	addSchedulerFlags(fs, opts)
+	path, code, ok := parseArgs(fs, opts, args, stderr)
+	if !ok {
+		return code
+	}
+	sched, code, ok := newScheduler(fs.Name(), opts, stderr)
+	if !ok {
+		return code
+	}
+
+	return withOutput(opts.output, stdout, stderr, func(w io.Writer) error {
+		var printer output.Printer = output.NewConsolePrinterWithWriter(w)
+		switch {
+		case opts.quiet:
+			printer = completionTimePrinter{w: w}
+		case opts.gantt:
+			printer = output.NewGanttPrinterWithWriter(w, output.TerminalWidth())
+		}
+		app := NewApp(jobReader(path, stdin), validator.NewGraphValidator(),
+			sched, printer).WithWorkers(opts.workers)
+		if !opts.quiet {
+			app.WithWarnings(stderr)
+		}
+		return app.Run()
+	})
+}
+
+// runValidate reads and validates a job file without scheduling it.
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("validate", stderr)
+	fs.IntVar(&opts.workers, "workers", 0, "override the worker count from the job file")
+	fs.BoolVar(&opts.quiet, "quiet", false, "print nothing on success, not even warnings")
+	path, code, ok := parseArgs(fs, opts, args, stderr)
+	if !ok {
+		return code
+	}
+
+	app := NewApp(jobReader(path, stdin), validator.NewGraphValidator(), nil, nil).
+		WithWorkers(opts.workers)
+	if !opts.quiet {
+		app.WithWarnings(stderr)
+	}
+	in, err := app.Check()
+	if err != nil {
+		return reportError(stderr, err)
+	}
+	if !opts.quiet {
+		fmt.Fprintf(stdout, "Job '%s' is valid: %d task(s), %d worker(s)\n",
+			in.Job.Name, in.Job.TaskCount(), in.Workers)
+	}
+	return exitOK
+}
+
+// runExport schedules a job file and writes the result in the requested format.
+func runExport(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("export", stderr)
+	fs.StringVar(&opts.format, "format", "", "output format: "+strings.Join(exportFormatNames(), ", "))
+	fs.IntVar(&opts.workers, "workers", 0, "override the worker count from the job file")
+	fs.StringVar(&opts.output, "output", "", "write the export to `file` instead of stdout")
+	addSchedulerFlags(fs, opts)
+	path, code, ok := parseArgs(fs, opts, args, stderr)
+	if !ok {
+		return code
+	}
+	sched, code, ok := newScheduler(fs.Name(), opts, stderr)
+	if !ok {
+		return code
+	}
+
+	newPrinter, ok := exportFormats[opts.format]
+	newGraphPrinter, isGraph := graphFormats[opts.format]
+	if !ok && !isGraph {
+		if opts.format == "" {
+			fmt.Fprintln(stderr, "export: --format is required")
+		} else {
+			fmt.Fprintf(stderr, "export: unknown format '%s' (available: %s)\n",
+				opts.format, strings.Join(exportFormatNames(), ", "))
+		}
+		return exitUsage
+	}
+
+	return withOutput(opts.output, stdout, stderr, func(w io.Writer) error {
+		app := NewApp(jobReader(path, stdin), validator.NewGraphValidator(),
+			sched, nil).WithWorkers(opts.workers).WithWarnings(stderr)
+		if isGraph {
+			in, result, err := app.Schedule()
+			if err != nil {
+				return err
+			}
+			newGraphPrinter(w).PrintGraph(in.Job, result)
+			return nil
+		}
+		app.printer = newPrinter(w)
+		return app.Run()
+	})
+}
+
+// runExecute runs the task commands of a job file and prints the measured
+// schedule with the status of every task, also when the run fails. Task
+// output is captured; with --logs it is saved per task. With --state the run
+// is journaled to a file; the resume command reads that file back, skips the
+// tasks that already succeeded and appends to it.
+func runExecute(name string, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	resume := name == "resume"
+	fs, opts := newFlagSet(name, stderr)
+	fs.IntVar(&opts.workers, "workers", 0, "override the worker count from the job file")
+	fs.StringVar(&opts.output, "output", "", "write the report to `file` instead of stdout")
+	fs.BoolVar(&opts.quiet, "quiet", false, "print only the completion time in milliseconds")
+	fs.BoolVar(&opts.gantt, "gantt", false, "draw a Gantt chart instead of the table")
+	fs.StringVar(&opts.priority, "priority", scheduler.DefaultPriorityRule,
+		"rule for picking ready tasks: "+strings.Join(scheduler.PriorityRuleNames(), ", "))
+	fs.StringVar(&opts.logs, "logs", "", "save each task's stdout and stderr in `dir`")
+	fs.StringVar(&opts.policy, "on-failure", string(executor.PolicyFailFast),
+		"what to do when a task fails: "+strings.Join(executor.FailurePolicyNames(), ", "))
+	if resume {
+		fs.StringVar(&opts.state, "state", "", "resume the run recorded in `file` (required)")
+	} else {
+		fs.StringVar(&opts.state, "state", "", "record the run in `file` so that it can be resumed")
+	}
+	path, code, ok := parseArgs(fs, opts, args, stderr)
+	if !ok {
+		return code
+	}
+	if resume && opts.state == "" {
+		fmt.Fprintf(stderr, "%s: --state is required\n", name)
+		return exitUsage
+	}
+	rule, err := scheduler.ParsePriorityRule(opts.priority)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return exitUsage
+	}
+	policy, err := executor.ParseFailurePolicy(opts.policy)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return exitUsage
+	}
+
+	app := NewApp(jobReader(path, stdin), validator.NewGraphValidator(), nil, nil).
+		WithWorkers(opts.workers)
+	if !opts.quiet {
+		app.WithWarnings(stderr)
+	}
+	in, err := app.Check()
+	if err != nil {
+		return reportError(stderr, err)
+	}
+
+	runner := executor.NewExecutorWithRule(rule).WithPolicy(policy)
+	if opts.state != "" {
+		stateFile, err := openState(opts.state, in.Job, resume, runner)
+		if err != nil {
+			return reportError(stderr, &stageError{stage: stageInput, err: err})
+		}
+		defer stateFile.Close()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	run, err := runner.Execute(ctx, in.Job, in.Workers)
+	if opts.logs != "" && run != nil {
+		if lerr := writeLogs(opts.logs, run.Outputs); lerr != nil {
+			fmt.Fprintf(stderr, "Error: could not write logs: %v\n", lerr)
+			return exitFailure
+		}
+	}
+	if run != nil && run.Result != nil {
+		code := withOutput(opts.output, stdout, stderr, func(w io.Writer) error {
+			var printer output.Printer = output.NewConsolePrinterWithWriter(w)
+			switch {
+			case opts.quiet:
+				printer = completionTimePrinter{w: w}
+			case opts.gantt:
+				printer = output.NewGanttPrinterWithWriter(w, output.TerminalWidth())
+			}
+			printer.Print(run.Result)
+			return nil
+		})
+		if code != exitOK {
+			return code
+		}
+	}
+	if err != nil {
+		var runErr *executor.RunError
+		if errors.As(err, &runErr) {
+			for _, taskErr := range runErr.Failed {
+				if tail := bytes.TrimSpace(run.Outputs[taskErr.TaskID].Stderr); len(tail) > 0 {
+					fmt.Fprintf(stderr, "--- stderr of task '%s' ---\n%s\n", taskErr.TaskID, tail)
+				}
+			}
+		}
+		if errors.Is(err, context.Canceled) {
+			err = errors.New("run interrupted")
+			if opts.state != "" {
+				err = fmt.Errorf("%w; continue it with: job-scheduler resume --state=%s %s", err, opts.state, path)
+			}
+		}
+		return reportError(stderr, &stageError{stage: stageExecution, err: err})
+	}
+	return exitOK
+}
+
+// openState opens the state file at path and attaches a journal to runner. For
+// a new run the file is truncated; when resuming, the recorded state is
+// checked against job and handed to runner, and the file is appended to.
+func openState(path string, job *model.Job, resume bool, runner *executor.Executor) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if resume {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading state file: %w", err)
+		}
+		state, err := executor.ReadState(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("state file %s: %w", path, err)
+		}
+		if err := state.Check(job); err != nil {
+			return nil, fmt.Errorf("state file %s: %w", path, err)
+		}
+		runner.WithResume(state)
+		// Drop a line left incomplete by a crash before appending to it.
+		if err := os.Truncate(path, int64(bytes.LastIndexByte(data, '\n')+1)); err != nil {
+			return nil, fmt.Errorf("repairing state file: %w", err)
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening state file: %w", err)
+	}
+	runner.WithJournal(executor.NewJournal(f))
+	return f, nil
+}
+
+// writeLogs saves each task's output as <dir>/<task>.stdout and .stderr.
+// Task IDs are escaped into file names (see logFileName), so every file
+// lands in dir and distinct IDs never share one.
+func writeLogs(dir string, outputs map[string]*executor.TaskOutput) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for id, out := range outputs {
+		base := filepath.Join(dir, logFileName(id))
+		if rel, err := filepath.Rel(dir, base); err != nil || rel != logFileName(id) {
+			return fmt.Errorf("log file for task '%s' would be outside %s", id, dir)
+		}
+		if err := os.WriteFile(base+".stdout", out.Stdout, 0o644); err != nil {
+			return err
+		}
+		if err := os.WriteFile(base+".stderr", out.Stderr, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logFileName encodes a task ID as a file name: path separators and other
+// unsafe characters are percent-escaped, and so are the dots of the IDs "."
+// and "..". Different IDs always give different names.
+func logFileName(id string) string {
+	if strings.Trim(id, ".") == "" {
+		return strings.Repeat("%2E", len(id))
+	}
+	return url.PathEscape(id)
+}
+
+// runServe serves the HTTP API until interrupted.
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("serve", stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: job-scheduler serve [flags]\n\nFlags:")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.addr, "addr", ":8080", "listen `address`")
+	addSchedulerFlags(fs, opts)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "serve: unexpected argument '%s'\n", fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+	sched, code, ok := newScheduler(fs.Name(), opts, stderr)
+	if !ok {
+		return code
+	}
+
+	srv := &http.Server{
+		Addr:              opts.addr,
+		Handler:           server.NewServer(validator.NewGraphValidator(), sched),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", opts.addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(listener) }()
+	fmt.Fprintf(stdout, "Listening on %s (POST /v1/schedule, POST /v1/validate)\n", listener.Addr())
+
+	select {
+	case err := <-errc:
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitFailure
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *cliOptions) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: job-scheduler %s [flags] <job-file>\n\nFlags:\n", name)
+		fs.PrintDefaults()
+	}
+	return fs, &cliOptions{}
+}
+
+// parseArgs parses flags that may appear before or after the job file
+// argument and returns the single positional argument. When ok is false the
+// command must stop and exit with code (e.g. after -h or a usage error).
+func parseArgs(fs *flag.FlagSet, opts *cliOptions, args []string, stderr io.Writer) (path string, code int, ok bool) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return "", exitOK, false
+			}
+			return "", exitUsage, false
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != 1 {
+		fmt.Fprintf(stderr, "%s: expected exactly one job file, got %d\n", fs.Name(), len(positional))
+		fs.Usage()
+		return "", exitUsage, false
+	}
+	if opts.workers < 0 {
+		fmt.Fprintf(stderr, "%s: --workers must be positive, got %d\n", fs.Name(), opts.workers)
+		return "", exitUsage, false
+	}
+	return positional[0], exitOK, true
+}
+
+// addSchedulerFlags registers the flags that select and tune the scheduler.
+func addSchedulerFlags(fs *flag.FlagSet, opts *cliOptions) {
+	fs.StringVar(&opts.priority, "priority", scheduler.DefaultPriorityRule,
+		"rule for picking ready tasks when workers are limited: "+
+			strings.Join(scheduler.PriorityRuleNames(), ", "))
+	fs.BoolVar(&opts.optimal, "optimal", false, "search for a provably optimal schedule (small jobs)")
+	fs.IntVar(&opts.maxTasks, "max-tasks", scheduler.DefaultOptimalMaxTasks,
+		"largest job searched exhaustively with --optimal")
+	fs.DurationVar(&opts.timeout, "timeout", scheduler.DefaultOptimalTimeout,
+		"time limit for --optimal; the best schedule found so far is returned")
+}
+
+// newScheduler builds the scheduler selected by the command-line options.
+func newScheduler(command string, opts *cliOptions, stderr io.Writer) (scheduler.Scheduler, int, bool) {
+	if opts.optimal {
+		return scheduler.NewOptimalSchedulerWithLimits(opts.maxTasks, opts.timeout), exitOK, true
+	}
+	rule, err := scheduler.ParsePriorityRule(opts.priority)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", command, err)
+		return nil, exitUsage, false
+	}
+	return scheduler.NewWorkerSchedulerWithRule(rule), exitOK, true
+}
+
+// jobReader returns a reader for the job file; "-" reads from stdin.
+func jobReader(path string, stdin io.Reader) input.Reader {
+	if path == "-" {
+		return input.NewFileReaderFrom(stdin, "")
+	}
+	return input.NewFileReader(path)
+}
+
+// withOutput runs fn with the selected output (a file or stdout) and
+// converts its error into an exit code.
+func withOutput(path string, stdout, stderr io.Writer, fn func(w io.Writer) error) int {
+	w := stdout
+	var file *os.File
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: could not create output file: %v\n", err)
+			return exitFailure
+		}
+		file = f
+		w = f
+	}
+
+	err := fn(w)
+	if file != nil {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			fmt.Fprintf(stderr, "Error: could not write output file: %v\n", cerr)
+			return exitFailure
+		}
+	}
+	if err != nil {
+		return reportError(stderr, err)
+	}
+	return exitOK
+}
+
+// reportError prints err to stderr and returns its exit code. Validation
+// failures are listed problem by problem, with any dependency cycle spelled out.
+func reportError(stderr io.Writer, err error) int {
+	var se *stageError
+	if errors.As(err, &se) && se.stage == stageValidation {
+		output.NewConsolePrinterWithWriter(stderr).PrintError(se.err)
+	} else {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+	}
+	return exitCode(err)
+}
+
+func exportFormatNames() []string {
+	names := make([]string, 0, len(exportFormats)+len(graphFormats))
+	for name := range exportFormats {
+		names = append(names, name)
+	}
+	for name := range graphFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completionTimePrinter prints only the minimum completion time, for scripts.
+type completionTimePrinter struct {
+	w io.Writer
+}
+
+func (p completionTimePrinter) Print(result *model.ScheduleResult) {
+	fmt.Fprintln(p.w, result.MinCompletionTime)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, `Usage:
+  job-scheduler                              interactive mode
+  job-scheduler schedule [flags] <job-file>  schedule a job and print the plan
+  job-scheduler validate [flags] <job-file>  check a job definition
+  job-scheduler export --format=<fmt> [flags] <job-file>
+                                             schedule a job and export the result
+  job-scheduler run [flags] <job-file>       run the task commands
+  job-scheduler resume --state=<file> [flags] <job-file>
+                                             finish an interrupted run
+  job-scheduler serve [--addr=:8080]         serve the HTTP API
+
+Job files may be JSON, YAML, TOML, Graphviz DOT or Mermaid; use "-" to read
+from stdin.
+Run "job-scheduler <command> -h" for the flags of a command.
+
+Exit codes:
+  0 success, 1 unexpected failure, 2 usage error, 3 input error,
+  4 validation error, 5 dependency cycle, 6 scheduling error,
+  7 task command failed or run interrupted`)
+}
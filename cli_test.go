@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCLI(t *testing.T) {
+	const (
+		cycle     = `{"tasks": [{"id": "A", "duration": 1, "dependencies": ["B"]}, {"id": "B", "duration": 1, "dependencies": ["A"]}]}`
+		undefined = `{"tasks": [{"id": "A", "duration": 1, "dependencies": ["Z"]}]}`
+		redundant = `{"tasks": [{"id": "a", "duration": 1}, {"id": "b", "duration": 1, "dependencies": ["a"]}, {"id": "c", "duration": 1, "dependencies": ["a", "b"]}]}`
+		failing   = `{"tasks": [{"id": "a", "duration": 1, "command": {"args": ["sh", "-c", "echo broken >&2; exit 1"]}}]}`
+		pooled    = `{"pool": [{"name": "slow"}, {"name": "fast", "speed": 2, "tags": ["gpu"]}], "tasks": [{"id": "a", "duration": 4, "requires": ["gpu"]}, {"id": "b", "duration": 2, "dependencies": ["a"]}]}`
+		packing   = `{"workers": 2, "tasks": [{"id": "a", "duration": 3}, {"id": "b", "duration": 3}, {"id": "c", "duration": 2}, {"id": "d", "duration": 2}, {"id": "e", "duration": 2}]}`
+	)
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "interactive", args: []string{}, stdin: "J\n1\nA\n3\n\n1\n", wantCode: exitOK, wantStdout: "Critical Path Calculator"},
+		{name: "schedule", args: []string{"schedule", "examples/job.json"}, wantCode: exitOK, wantStdout: "11"},
+		{name: "schedule quiet", args: []string{"schedule", "--quiet", "examples/job.json"}, wantCode: exitOK, wantStdout: "11\n"},
+		{name: "flags after file", args: []string{"schedule", "examples/job.json", "--quiet", "--workers", "1"}, wantCode: exitOK, wantStdout: "19\n"},
+		{name: "schedule stdin", args: []string{"schedule", "--quiet", "-"}, stdin: `{"tasks": [{"id": "A", "duration": 4}]}`, wantCode: exitOK, wantStdout: "4\n"},
+		{name: "validate", args: []string{"validate", "examples/job.yaml"}, wantCode: exitOK, wantStdout: "Job 'J' is valid: 6 task(s), 2 worker(s)"},
+		{name: "schedule dot", args: []string{"schedule", "--quiet", "examples/job.dot"}, wantCode: exitOK, wantStdout: "11\n"},
+		{name: "schedule mermaid", args: []string{"schedule", "--quiet", "examples/job.mmd"}, wantCode: exitOK, wantStdout: "11\n"},
+		{name: "validate quiet", args: []string{"validate", "--quiet", "examples/job.toml"}, wantCode: exitOK},
+		{name: "export text", args: []string{"export", "--format=text", "examples/job.json"}, wantCode: exitOK, wantStdout: "11"},
+		{name: "export gantt", args: []string{"export", "--format=gantt", "examples/job.json"}, wantCode: exitOK, wantStdout: "Gantt chart: J (2 worker(s), 11 unit(s))"},
+		{name: "export json", args: []string{"export", "--format=json", "examples/job.json"}, wantCode: exitOK, wantStdout: `"min_completion_time": 11`},
+		{name: "export csv", args: []string{"export", "--format", "csv", "examples/job.json"}, wantCode: exitOK, wantStdout: "schema_version,job,workers"},
+		{name: "export dot", args: []string{"export", "--format=dot", "examples/job.json"}, wantCode: exitOK, wantStdout: `"A" -> "D" [color=red, penwidth=2];`},
+		{name: "export mermaid", args: []string{"export", "--format=mermaid", "examples/job.json"}, wantCode: exitOK, wantStdout: "flowchart LR"},
+		{name: "export graph of cycle", args: []string{"export", "--format=dot", "-"}, stdin: cycle, wantCode: exitCycle},
+		{name: "schedule gantt", args: []string{"schedule", "--gantt", "examples/job.json"}, wantCode: exitOK, wantStdout: "# critical"},
+		{name: "priority", args: []string{"schedule", "--quiet", "--priority=lrp", "--workers=1", "examples/job.json"}, wantCode: exitOK, wantStdout: "19\n"},
+		{name: "unknown priority", args: []string{"export", "--format=csv", "--priority=random", "examples/job.json"}, wantCode: exitUsage, wantStderr: "unknown priority rule 'random'"},
+		{name: "optimal", args: []string{"schedule", "--quiet", "--optimal", "-"}, stdin: packing, wantCode: exitOK, wantStdout: "6\n"},
+		{name: "list schedule", args: []string{"schedule", "--quiet", "-"}, stdin: packing, wantCode: exitOK, wantStdout: "7\n"},
+		{name: "pool", args: []string{"schedule", "-"}, stdin: pooled, wantCode: exitOK, wantStdout: "Worker pool: W1 slow x1, W2 fast x2 [gpu]"},
+		{name: "pool workers override", args: []string{"schedule", "--workers=3", "-"}, stdin: pooled, wantCode: exitInput, wantStderr: "declares a pool of 2 worker(s)"},
+		{name: "unrunnable task", args: []string{"validate", "-"}, stdin: `{"tasks": [{"id": "a", "duration": 1, "requires": ["gpu"]}]}`, wantCode: exitValidation, wantStderr: "no worker can run task 'a'"},
+		{name: "resources", args: []string{"schedule", "--quiet", "-"}, stdin: `{"resources": {"license": 1}, "tasks": [{"id": "a", "duration": 3, "resources": {"license": 1}}, {"id": "b", "duration": 3, "resources": {"license": 1}}]}`, wantCode: exitOK, wantStdout: "6\n"},
+		{name: "demand over capacity", args: []string{"validate", "-"}, stdin: `{"resources": {"cpu": 1}, "tasks": [{"id": "a", "duration": 1, "resources": {"cpu": 2}}]}`, wantCode: exitValidation, wantStderr: "capacity of 1"},
+		{name: "typed link", args: []string{"schedule", "--quiet", "-"}, stdin: `{"tasks": [{"id": "a", "duration": 4}, {"id": "b", "duration": 5, "dependencies": [{"id": "a", "type": "SS", "lag": 1}]}]}`, wantCode: exitOK, wantStdout: "6\n"},
+		{name: "lint warning", args: []string{"validate", "-"}, stdin: redundant, wantCode: exitOK, wantStderr: "warning: [task.c.dependencies]"},
+		{name: "quiet hides warnings", args: []string{"schedule", "--quiet", "-"}, stdin: redundant, wantCode: exitOK, wantStdout: "3\n"},
+		{name: "serve with argument", args: []string{"serve", "job.json"}, wantCode: exitUsage, wantStderr: "unexpected argument 'job.json'"},
+		{name: "run failure", args: []string{"run", "-"}, stdin: failing, wantCode: exitExecution, wantStderr: "--- stderr of task 'a' ---\nbroken"},
+		{name: "run default priority", args: []string{"run", "-"}, stdin: `{"workers": 1, "tasks": [{"id": "a", "duration": 1}, {"id": "b", "duration": 1, "dependencies": ["a"]}]}`, wantCode: exitOK, wantStdout: "Priority rule: id"},
+		{name: "run continue", args: []string{"run", "--on-failure=continue", "-"}, stdin: failing, wantCode: exitExecution, wantStdout: "failed"},
+		{name: "unknown failure policy", args: []string{"run", "--on-failure=ignore", "-"}, stdin: failing, wantCode: exitUsage, wantStderr: "unknown failure policy 'ignore'"},
+		{name: "resume without state", args: []string{"resume", "-"}, stdin: failing, wantCode: exitUsage, wantStderr: "resume: --state is required"},
+		{name: "run without program", args: []string{"run", "-"}, stdin: `{"tasks": [{"id": "a", "duration": 1, "command": {"args": []}}]}`, wantCode: exitInput, wantStderr: "tasks[0].command.args"},
+		{name: "help", args: []string{"help"}, wantCode: exitOK, wantStdout: "Exit codes:"},
+		{name: "unknown command", args: []string{"plan"}, wantCode: exitUsage, wantStderr: "unknown command 'plan'"},
+		{name: "missing file argument", args: []string{"schedule"}, wantCode: exitUsage, wantStderr: "expected exactly one job file, got 0"},
+		{name: "negative workers", args: []string{"schedule", "--workers=-1", "examples/job.json"}, wantCode: exitUsage, wantStderr: "--workers must be positive"},
+		{name: "export without format", args: []string{"export", "examples/job.json"}, wantCode: exitUsage, wantStderr: "--format is required"},
+		{name: "export unknown format", args: []string{"export", "--format=pdf", "examples/job.json"}, wantCode: exitUsage, wantStderr: "unknown format 'pdf' (available: csv, dot, gantt, json, mermaid, text)"},
+		{name: "missing file", args: []string{"schedule", "examples/nope.json"}, wantCode: exitInput, wantStderr: "input error"},
+		{name: "bad input", args: []string{"validate", "-"}, stdin: `{"tasks": [{"id": "A"}]}`, wantCode: exitInput, wantStderr: "tasks[0].duration"},
+		{name: "undefined dependency", args: []string{"validate", "-"}, stdin: undefined, wantCode: exitValidation, wantStderr: "undefined task 'Z'"},
+		{name: "several problems", args: []string{"validate", "-"}, stdin: `{"tasks": [{"id": "A", "duration": 1, "dependencies": ["Z", "A"]}]}`, wantCode: exitValidation, wantStderr: "2 problem(s)"},
+		{name: "cycle and undefined", args: []string{"validate", "-"}, stdin: `{"tasks": [{"id": "A", "duration": 1, "dependencies": ["B"]}, {"id": "B", "duration": 1, "dependencies": ["A", "Z"]}]}`, wantCode: exitValidation, wantStderr: "cycle"},
+		{name: "cycle", args: []string{"schedule", "-"}, stdin: cycle, wantCode: exitCycle, wantStderr: "cycle 1: A -> B -> A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runCLI(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("exit code %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("stdout %q does not contain %q", stdout.String(), tt.wantStdout)
+			}
+			if tt.wantStderr == "" && stderr.Len() > 0 && code == exitOK {
+				t.Errorf("stderr %q, want nothing", stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr %q does not contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunCLIExecute(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	logs := t.TempDir()
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"run", "--logs", logs, "examples/pipeline.yaml"}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "ms") {
+		t.Errorf("report does not use milliseconds:\n%s", stdout.String())
+	}
+	data, err := os.ReadFile(filepath.Join(logs, "build.stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "built linux/amd64\n" {
+		t.Errorf("build.stdout = %q", data)
+	}
+}
+
+func TestRunCLIOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.txt")
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"schedule", "--quiet", "--output", path, "examples/job.json"},
+		strings.NewReader(""), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout %q, want nothing", stdout.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "11\n" {
+		t.Errorf("output file %q, want %q", data, "11\n")
+	}
+}
+
+func TestLogFileName(t *testing.T) {
+	tests := map[string]string{
+		"build":  "build",
+		"a/b":    "a%2Fb",
+		"a_b":    "a_b",
+		".":      "%2E",
+		"..":     "%2E%2E",
+		"v1.2":   "v1.2",
+		"a b":    "a%20b",
+		`a\b`:    "a%5Cb",
+		"../etc": "..%2Fetc",
+	}
+	seen := make(map[string]string)
+	for id, want := range tests {
+		got := logFileName(id)
+		if got != want {
+			t.Errorf("logFileName(%q) = %q, want %q", id, got, want)
+		}
+		if other, dup := seen[got]; dup {
+			t.Errorf("%q and %q share the log file %q", id, other, got)
+		}
+		seen[got] = id
+	}
+}
@@ -0,0 +1,479 @@
+// Package executor runs the commands of a job on a pool of local workers,
+// respecting dependencies, and reports the measured schedule.
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"wingie_case/model"
+	"wingie_case/scheduler"
+)
+
+// TaskOutput holds what a task's command wrote and how it exited.
+type TaskOutput struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int // -1 when the process could not start or was killed
+}
+
+// TaskError reports a task whose command failed on its last attempt.
+type TaskError struct {
+	TaskID   string
+	ExitCode int
+	Attempts int
+	Err      error
+}
+
+func (e *TaskError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("task '%s' failed after %d attempts: %v", e.TaskID, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("task '%s' failed: %v", e.TaskID, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// RunError lists the tasks that failed in a run, in the order they failed.
+type RunError struct {
+	Failed []*TaskError
+}
+
+func (e *RunError) Error() string {
+	if len(e.Failed) == 1 {
+		return e.Failed[0].Error()
+	}
+	msgs := make([]string, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		msgs = append(msgs, f.Error())
+	}
+	return fmt.Sprintf("%d tasks failed: %s", len(e.Failed), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual task errors.
+func (e *RunError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// Run is the outcome of Execute.
+type Run struct {
+	// Result holds the measured times in milliseconds since the run
+	// started (TimeUnit "ms") and the status of every task. It is set
+	// whenever the run started, including failed and cancelled runs.
+	Result *model.ScheduleResult
+	// Outputs holds the captured output of every task that ran, by ID;
+	// for retried tasks it is the output of the last attempt.
+	Outputs map[string]*TaskOutput
+}
+
+// Executor runs jobs with a bounded number of worker goroutines, one per
+// worker of the job's pool. Whenever a worker is free it starts the ready
+// task ranked first by the priority rule among those it may run (see
+// model.Worker.CanRun), as the limited-worker scheduler does; tasks without a command finish
+// immediately. A failed command is retried as its Command allows; once a
+// task has failed for good the failure policy decides how the run goes on.
+type Executor struct {
+	rule    scheduler.PriorityRule
+	policy  FailurePolicy
+	journal *Journal
+	resume  *State
+}
+
+// NewExecutor creates a fail-fast Executor that picks ready tasks with
+// scheduler.DefaultPriorityRule.
+func NewExecutor() *Executor {
+	rule, _ := scheduler.ParsePriorityRule(scheduler.DefaultPriorityRule)
+	return NewExecutorWithRule(rule)
+}
+
+// NewExecutorWithRule creates a fail-fast Executor that picks ready tasks with rule.
+func NewExecutorWithRule(rule scheduler.PriorityRule) *Executor {
+	return &Executor{rule: rule, policy: PolicyFailFast}
+}
+
+// WithPolicy sets the failure policy.
+func (e *Executor) WithPolicy(policy FailurePolicy) *Executor {
+	e.policy = policy
+	return e
+}
+
+// WithJournal records every run and task state transition in j.
+func (e *Executor) WithJournal(j *Journal) *Executor {
+	e.journal = j
+	return e
+}
+
+// WithResume continues the run recorded in state: tasks that succeeded
+// there, together with all their dependencies, are reported as
+// model.StatusReused instead of being run again.
+func (e *Executor) WithResume(state *State) *Executor {
+	e.resume = state
+	return e
+}
+
+// finished is sent by a worker goroutine when a task ends.
+type finished struct {
+	id     string
+	worker int // index in the pool
+	timing scheduler.Timing
+	output *TaskOutput
+	err    *TaskError
+}
+
+// Execute runs job on the given number of workers. It returns a *RunError
+// when tasks failed; cancelling ctx kills the running commands and makes
+// Execute return ctx.Err(). In both cases Run.Result reports how far the
+// run got. The job must be valid (see validator.GraphValidator); when
+// resuming, the state must have been recorded for it (see State.Check).
+// A task only starts while its resource demands fit in the job's capacities,
+// and only on a worker of the job's pool that has every tag it requires;
+// with a pool, workers must be its size. Commands take as long as they take, so typed links (model.Link) cannot be
+// honoured as planned: every dependency is waited for until it finishes.
+func (e *Executor) Execute(ctx context.Context, job *model.Job, workers int) (*Run, error) {
+	if workers <= 0 {
+		return nil, fmt.Errorf("workers must be positive, got %d", workers)
+	}
+	order, err := job.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	if err := scheduler.CheckDemands(job); err != nil {
+		return nil, err
+	}
+	pool, err := scheduler.WorkerPool(job, workers)
+	if err != nil {
+		return nil, err
+	}
+	if err := scheduler.CheckEligibility(job, pool); err != nil {
+		return nil, err
+	}
+	if e.resume != nil {
+		if err := e.resume.Check(job); err != nil {
+			return nil, err
+		}
+	}
+	e.journal.begin(job, workers)
+
+	// runCtx is cancelled by the fail-fast policy to kill running tasks.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	origin := time.Now()
+	// Each worker has its own queue, so that tasks go to an eligible one.
+	work := make([]chan string, len(pool))
+	free := make([]bool, len(pool))
+	done := make(chan finished)
+	var wg sync.WaitGroup
+	for w := range pool {
+		work[w] = make(chan string)
+		free[w] = true
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for id := range work[w] {
+				f := runTask(runCtx, job.Tasks[id], pool[w].ID, origin)
+				f.worker = w
+				done <- f
+			}
+		}(w)
+	}
+
+	successors := job.SuccessorMap()
+	before := e.rule.Prepare(job)
+	run := &Run{Outputs: make(map[string]*TaskOutput, job.TaskCount())}
+	timings := e.reused(job, order)
+	pending := make(map[string]int, job.TaskCount())
+	ready := make(map[string]scheduler.ReadyTask)
+	seq := 0
+	for _, id := range order {
+		if _, done := timings[id]; done {
+			continue
+		}
+		for _, dep := range job.Predecessors(id) {
+			if _, done := timings[dep]; !done {
+				pending[id]++
+			}
+		}
+		if pending[id] == 0 {
+			ready[id] = scheduler.ReadyTask{ID: id, Seq: seq}
+			seq++
+		}
+	}
+	usage := scheduler.NewResourceUsage(job.Resources)
+	fits := func(id string) bool {
+		return usage.Fits(job.Tasks[id]) && freeWorker(pool, free, job.Tasks[id]) >= 0
+	}
+	skippedAt := make(map[string]int)   // when a dependency of the task failed
+	var descendants *model.Reachability // built on the first failure under PolicyContinue
+	var failed []*TaskError
+	stopped := false
+	idle, running := workers, 0
+	for {
+		for idle > 0 && len(ready) > 0 && !stopped && ctx.Err() == nil {
+			next, ok := first(ready, before, fits)
+			if !ok {
+				break // wait for running tasks to release resources or workers
+			}
+			delete(ready, next.ID)
+			usage.Acquire(job.Tasks[next.ID])
+			w := freeWorker(pool, free, job.Tasks[next.ID])
+			free[w] = false
+			work[w] <- next.ID
+			e.journal.task(next.ID, statusStarted, 0, nil)
+			idle--
+			running++
+		}
+		if running == 0 {
+			break
+		}
+
+		f := <-done
+		free[f.worker] = true
+		idle++
+		running--
+		usage.Release(job.Tasks[f.id])
+		run.Outputs[f.id] = f.output
+		switch {
+		case f.err == nil:
+			f.timing.Status = model.StatusSucceeded
+			for _, next := range successors[f.id] {
+				pending[next]--
+				if pending[next] == 0 {
+					ready[next] = scheduler.ReadyTask{ID: next, ReadyAt: f.timing.Finish, Seq: seq}
+					seq++
+				}
+			}
+		case runCtx.Err() != nil:
+			f.timing.Status = model.StatusCancelled
+		case e.policy == PolicyFailFast:
+			f.timing.Status = model.StatusFailed
+			failed = append(failed, f.err)
+			stopped = true
+			cancel()
+		default:
+			f.timing.Status = model.StatusFailed
+			failed = append(failed, f.err)
+			if descendants == nil {
+				descendants = job.DescendantSets()
+			}
+			for _, id := range descendants.Set(f.id) {
+				if _, seen := skippedAt[id]; !seen {
+					skippedAt[id] = f.timing.Finish
+				}
+			}
+		}
+		timings[f.id] = f.timing
+		e.journal.task(f.id, string(f.timing.Status), f.timing.Attempts, f.err)
+	}
+	for _, queue := range work {
+		close(queue)
+	}
+	wg.Wait()
+
+	// Tasks that never started are placed when they were given up on, but
+	// never before one of their dependencies ended.
+	end := int(time.Since(origin).Milliseconds())
+	for _, id := range order {
+		if _, seen := timings[id]; seen {
+			continue
+		}
+		at, ok := skippedAt[id]
+		if !ok {
+			at = end
+		}
+		for _, dep := range job.Predecessors(id) {
+			at = max(at, timings[dep].Finish)
+		}
+		timings[id] = scheduler.Timing{Start: at, Finish: at, Status: model.StatusSkipped}
+		e.journal.task(id, string(model.StatusSkipped), 0, nil)
+	}
+	result, err := scheduler.ResultFromTimings(job, workers, timings)
+	if err != nil {
+		return run, e.withJournalErr(err)
+	}
+	result.TimeUnit = "ms"
+	result.Resources = job.Resources
+	result.PriorityRule = e.rule.Name()
+	run.Result = result
+
+	if err := ctx.Err(); err != nil {
+		return run, e.withJournalErr(err)
+	}
+	if len(failed) > 0 {
+		return run, e.withJournalErr(&RunError{Failed: failed})
+	}
+	return run, e.withJournalErr(nil)
+}
+
+// withJournalErr adds the journal's write error, if any, to the error a run
+// ends with. A failed or interrupted run is the one that needs resuming, so
+// a state file that could not be written must never go unreported.
+// errors.Is and errors.As see both errors.
+func (e *Executor) withJournalErr(err error) error {
+	jerr := e.journal.Err()
+	switch {
+	case jerr == nil:
+		return err
+	case err == nil:
+		return fmt.Errorf("writing run state: %w", jerr)
+	}
+	return fmt.Errorf("%w (also failed writing run state: %w)", err, jerr)
+}
+
+// reused returns the timings of the tasks that succeeded in the resumed run.
+// A task only counts when all its dependencies do, so nothing reused ever
+// depends on a task that runs again.
+func (e *Executor) reused(job *model.Job, order []string) map[string]scheduler.Timing {
+	timings := make(map[string]scheduler.Timing, job.TaskCount())
+	if e.resume == nil {
+		return timings
+	}
+	for _, id := range order {
+		if !e.resume.Succeeded[id] {
+			continue
+		}
+		reusable := true
+		for _, dep := range job.Predecessors(id) {
+			_, ok := timings[dep]
+			reusable = reusable && ok
+		}
+		if reusable {
+			timings[id] = scheduler.Timing{Status: model.StatusReused}
+		}
+	}
+	return timings
+}
+
+// freeWorker returns the index of the free worker of pool that can run
+// task, preferring the one with the fewest tags so that tagged workers stay
+// available for the tasks that need them, then the lowest index. It returns
+// -1 when no eligible worker is free.
+func freeWorker(pool []model.Worker, free []bool, task *model.Task) int {
+	best := -1
+	for w, worker := range pool {
+		if free[w] && worker.CanRun(task) && (best < 0 || len(worker.Tags) < len(pool[best].Tags)) {
+			best = w
+		}
+	}
+	return best
+}
+
+// first returns the ready task that before ranks first among those that fit
+// in the free resources and workers, or false when none does.
+func first(ready map[string]scheduler.ReadyTask, before func(a, b scheduler.ReadyTask) bool,
+	fits func(id string) bool) (scheduler.ReadyTask, bool) {
+	candidates := make([]scheduler.ReadyTask, 0, len(ready))
+	for _, rt := range ready {
+		if fits(rt.ID) {
+			candidates = append(candidates, rt)
+		}
+	}
+	if len(candidates) == 0 {
+		return scheduler.ReadyTask{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool { return before(candidates[i], candidates[j]) })
+	return candidates[0], true
+}
+
+// runTask runs one task's command, retrying it as the command allows, and
+// measures it in milliseconds since origin; the waits between attempts count
+// towards its duration.
+func runTask(ctx context.Context, task *model.Task, worker int, origin time.Time) finished {
+	f := finished{id: task.ID, output: &TaskOutput{}}
+	start := time.Now()
+	attempts := 1
+	if c := task.Command; c != nil {
+		for ; ; attempts++ {
+			f.output = &TaskOutput{}
+			f.err = runCommand(ctx, task, f.output)
+			if f.err == nil || attempts > c.Retries || !wait(ctx, backoff(c.Backoff, attempts)) {
+				break
+			}
+		}
+	}
+	if f.err != nil {
+		f.err.Attempts = attempts
+	}
+	f.timing = scheduler.Timing{
+		Start:    int(start.Sub(origin).Milliseconds()),
+		Finish:   int(time.Since(origin).Milliseconds()),
+		WorkerID: worker,
+		Attempts: attempts,
+	}
+	return f
+}
+
+// MaxBackoff caps the wait between two attempts of a task.
+const MaxBackoff = 5 * time.Minute
+
+// backoff returns the wait after the given failed attempt: base, doubled
+// for every earlier attempt, but never more than MaxBackoff.
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, MaxBackoff)
+}
+
+// wait sleeps for d and reports whether ctx is still alive afterwards.
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// killGrace is how long a killed command may keep its output pipes open,
+// e.g. through a child process, before Execute stops waiting for it.
+const killGrace = time.Second
+
+// runCommand runs one attempt of the task's command within its timeout,
+// capturing its output into out.
+func runCommand(ctx context.Context, task *model.Task, out *TaskOutput) *TaskError {
+	c := task.Command
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, c.Args[0], c.Args[1:]...)
+	cmd.Env = append(os.Environ(), c.Env...)
+	cmd.Dir = c.Dir
+	cmd.WaitDelay = killGrace
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	out.Stdout, out.Stderr = stdout.Bytes(), stderr.Bytes()
+	out.ExitCode = cmd.ProcessState.ExitCode()
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		out.ExitCode = -1
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", c.Timeout)
+	}
+	return &TaskError{TaskID: task.ID, ExitCode: out.ExitCode, Err: err}
+}
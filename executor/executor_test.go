@@ -0,0 +1,429 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"wingie_case/model"
+)
+
+// shellTask returns a task that runs script with sh.
+func shellTask(t *testing.T, id string, deps []string, script string) *model.Task {
+	t.Helper()
+	task, err := model.NewTask(id, 1, deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.Command = &model.Command{Args: []string{"sh", "-c", script}}
+	return task
+}
+
+func jobOf(t *testing.T, tasks ...*model.Task) *model.Job {
+	t.Helper()
+	job := model.NewJob("J")
+	for _, task := range tasks {
+		if err := job.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return job
+}
+
+func statuses(run *Run) map[string]model.TaskStatus {
+	got := make(map[string]model.TaskStatus)
+	for _, ts := range run.Result.TaskSchedules {
+		got[ts.TaskID] = ts.Status
+	}
+	return got
+}
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestExecute(t *testing.T) {
+	requireShell(t)
+	dir := t.TempDir()
+	build := shellTask(t, "build", []string{"fetch"}, `echo "$TARGET in $(pwd)"`)
+	build.Command.Env = []string{"TARGET=linux"}
+	build.Command.Dir = dir
+	release, _ := model.NewTask("release", 1, []string{"build", "lint"})
+	job := jobOf(t,
+		shellTask(t, "fetch", nil, "sleep 0.05; echo fetched"),
+		shellTask(t, "lint", nil, "echo warning >&2"),
+		build,
+		release,
+	)
+
+	run, err := NewExecutor().Execute(context.Background(), job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := run.Result
+	if result.TimeUnit != "ms" || result.Workers != 2 || len(result.TaskSchedules) != 4 {
+		t.Fatalf("result %+v", result)
+	}
+	times := make(map[string]model.TaskSchedule)
+	for _, ts := range result.TaskSchedules {
+		times[ts.TaskID] = ts
+		if ts.WorkerID < 1 || ts.WorkerID > 2 {
+			t.Errorf("%s ran on worker %d", ts.TaskID, ts.WorkerID)
+		}
+	}
+	if times["build"].EarliestStart < times["fetch"].EarliestFinish ||
+		times["release"].EarliestStart < times["build"].EarliestFinish {
+		t.Errorf("dependencies not respected: %+v", times)
+	}
+	if got := string(run.Outputs["build"].Stdout); got != "linux in "+dir+"\n" {
+		t.Errorf("build wrote %q", got)
+	}
+	if got := string(run.Outputs["lint"].Stderr); got != "warning\n" {
+		t.Errorf("lint wrote %q to stderr", got)
+	}
+	if out := run.Outputs["release"]; out == nil || len(out.Stdout) != 0 {
+		t.Errorf("release (no command) output %+v", out)
+	}
+}
+
+// With the default fail-fast policy no new task starts after a failure; the
+// error names the task and the result reports the skipped ones.
+func TestExecuteFailure(t *testing.T) {
+	requireShell(t)
+	job := jobOf(t,
+		shellTask(t, "a", nil, "echo oops >&2; exit 3"),
+		shellTask(t, "b", []string{"a"}, "echo b"),
+	)
+	run, err := NewExecutor().Execute(context.Background(), job, 1)
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.TaskID != "a" || taskErr.ExitCode != 3 {
+		t.Fatalf("error = %v, want task a to fail with exit code 3", err)
+	}
+	if got := statuses(run); got["a"] != model.StatusFailed || got["b"] != model.StatusSkipped {
+		t.Errorf("statuses %v, want a failed and b skipped", got)
+	}
+	if _, ran := run.Outputs["b"]; ran {
+		t.Error("b ran after a failed")
+	}
+	if got := string(run.Outputs["a"].Stderr); got != "oops\n" {
+		t.Errorf("a wrote %q to stderr", got)
+	}
+}
+
+// Tasks sharing a resource never run at the same time, even with free workers.
+func TestExecuteResources(t *testing.T) {
+	requireShell(t)
+	a := shellTask(t, "a", nil, "sleep 0.05")
+	b := shellTask(t, "b", nil, "sleep 0.05")
+	a.Resources = map[string]int{"license": 1}
+	b.Resources = map[string]int{"license": 1}
+	job := jobOf(t, a, b)
+	job.Resources = map[string]int{"license": 1}
+	run, err := NewExecutor().Execute(context.Background(), job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := make(map[string]model.TaskSchedule)
+	for _, ts := range run.Result.TaskSchedules {
+		times[ts.TaskID] = ts
+	}
+	if first, second := times["a"], times["b"]; first.EarliestStart < second.EarliestFinish &&
+		second.EarliestStart < first.EarliestFinish {
+		t.Errorf("a (%d-%d) and b (%d-%d) overlap", first.EarliestStart, first.EarliestFinish,
+			second.EarliestStart, second.EarliestFinish)
+	}
+}
+
+// Under the continue policy only the tasks downstream of a failure are
+// skipped; independent branches still run.
+func TestExecuteContinue(t *testing.T) {
+	requireShell(t)
+	job := jobOf(t,
+		shellTask(t, "a", nil, "exit 1"),
+		shellTask(t, "b", []string{"a"}, "true"),
+		shellTask(t, "c", []string{"b"}, "true"),
+		shellTask(t, "d", nil, "sleep 0.05"),
+		shellTask(t, "e", nil, "exit 2"),
+	)
+	run, err := NewExecutor().WithPolicy(PolicyContinue).Execute(context.Background(), job, 1)
+	var runErr *RunError
+	if !errors.As(err, &runErr) || len(runErr.Failed) != 2 {
+		t.Fatalf("error = %v, want a RunError with two failed tasks", err)
+	}
+	want := map[string]model.TaskStatus{
+		"a": model.StatusFailed, "b": model.StatusSkipped, "c": model.StatusSkipped,
+		"d": model.StatusSucceeded, "e": model.StatusFailed,
+	}
+	got := statuses(run)
+	for id, status := range want {
+		if got[id] != status {
+			t.Errorf("%s is %s, want %s", id, got[id], status)
+		}
+	}
+}
+
+// A failing command is retried until it succeeds or runs out of retries.
+func TestExecuteRetries(t *testing.T) {
+	requireShell(t)
+	counter := filepath.Join(t.TempDir(), "attempts")
+	flaky := shellTask(t, "flaky", nil, `echo x >> "$COUNTER"; [ "$(wc -l < "$COUNTER")" -ge 3 ]`)
+	flaky.Command.Env = []string{"COUNTER=" + counter}
+	flaky.Command.Retries = 2
+	flaky.Command.Backoff = time.Millisecond
+	broken := shellTask(t, "broken", nil, "exit 1")
+	broken.Command.Retries = 1
+
+	run, err := NewExecutor().WithPolicy(PolicyContinue).Execute(context.Background(), jobOf(t, flaky, broken), 2)
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.TaskID != "broken" || taskErr.Attempts != 2 {
+		t.Fatalf("error = %v, want broken to fail after 2 attempts", err)
+	}
+	if !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("error %q does not mention the attempts", err)
+	}
+	for _, ts := range run.Result.TaskSchedules {
+		if ts.TaskID == "flaky" && (ts.Status != model.StatusSucceeded || ts.Attempts != 3) {
+			t.Errorf("flaky: %s after %d attempt(s), want succeeded after 3", ts.Status, ts.Attempts)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		base    time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{base: time.Second, attempt: 1, want: time.Second},
+		{base: time.Second, attempt: 2, want: 2 * time.Second},
+		{base: time.Second, attempt: 4, want: 8 * time.Second},
+		{base: time.Second, attempt: 9, want: 256 * time.Second},
+		{base: time.Second, attempt: 10, want: MaxBackoff},
+		{base: time.Second, attempt: 1000, want: MaxBackoff},
+		{base: time.Hour, attempt: 1, want: MaxBackoff},
+		{base: 0, attempt: 5, want: 0},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.base, tt.attempt); got != tt.want {
+			t.Errorf("backoff(%s, %d) = %s, want %s", tt.base, tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	requireShell(t)
+	slow := shellTask(t, "slow", nil, "exec sleep 5")
+	slow.Command.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	_, err := NewExecutor().Execute(context.Background(), jobOf(t, slow), 1)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("timed out run took %s", elapsed)
+	}
+}
+
+func TestParseFailurePolicy(t *testing.T) {
+	if p, err := ParseFailurePolicy(" Continue "); err != nil || p != PolicyContinue {
+		t.Errorf("ParseFailurePolicy(Continue) = %q, %v", p, err)
+	}
+	if _, err := ParseFailurePolicy("retry"); err == nil || !strings.Contains(err.Error(), "fail-fast, continue") {
+		t.Errorf("error = %v, want the available policies", err)
+	}
+}
+
+// A run that fails half way is resumed from its journal, also after a crash
+// cut the journal's last line: only the tasks that did not succeed run
+// again.
+func TestExecuteResume(t *testing.T) {
+	requireShell(t)
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	count := filepath.Join(dir, "count")
+
+	job := jobOf(t,
+		shellTask(t, "a", nil, "echo a >> "+count),
+		shellTask(t, "b", []string{"a"}, "test -e "+ready),
+		shellTask(t, "c", []string{"b"}, "echo c >> "+count),
+		shellTask(t, "d", []string{"a"}, "echo d >> "+count),
+	)
+
+	var journal bytes.Buffer
+	run, err := NewExecutor().WithPolicy(PolicyContinue).WithJournal(NewJournal(&journal)).
+		Execute(context.Background(), job, 2)
+	var runErr *RunError
+	if !errors.As(err, &runErr) || len(runErr.Failed) != 1 || runErr.Failed[0].TaskID != "b" {
+		t.Fatalf("error = %v, want b to fail", err)
+	}
+	want := map[string]model.TaskStatus{
+		"a": model.StatusSucceeded, "b": model.StatusFailed,
+		"c": model.StatusSkipped, "d": model.StatusSucceeded,
+	}
+	for id, status := range statuses(run) {
+		if status != want[id] {
+			t.Errorf("first run: %s is %s, want %s", id, status, want[id])
+		}
+	}
+
+	for _, cut := range []bool{false, true} {
+		text := journal.String()
+		if cut {
+			text = text[:len(text)-5]
+		}
+		state, err := ReadState(strings.NewReader(text))
+		if err != nil {
+			t.Fatalf("cut %v: %v", cut, err)
+		}
+		if !state.Succeeded["a"] || !state.Succeeded["d"] || state.Succeeded["b"] {
+			t.Errorf("cut %v: succeeded %v, want a and d", cut, state.Succeeded)
+		}
+	}
+
+	state, err := ReadState(&journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ready, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var resumed bytes.Buffer
+	run, err = NewExecutor().WithJournal(NewJournal(&resumed)).WithResume(state).
+		Execute(context.Background(), job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]model.TaskStatus{
+		"a": model.StatusReused, "b": model.StatusSucceeded,
+		"c": model.StatusSucceeded, "d": model.StatusReused,
+	}
+	for id, status := range statuses(run) {
+		if status != want[id] {
+			t.Errorf("resumed run: %s is %s, want %s", id, status, want[id])
+		}
+	}
+	ran, err := os.ReadFile(count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Fields(string(ran)); len(lines) != 3 {
+		t.Errorf("tasks a, c and d ran %v, want each once", lines)
+	}
+}
+
+func TestExecuteResumeOtherJob(t *testing.T) {
+	a, _ := model.NewTask("a", 1, nil)
+	job := jobOf(t, a)
+	state := &State{Job: "J", Fingerprint: "something else", Succeeded: map[string]bool{"a": true}}
+	if _, err := NewExecutor().WithResume(state).Execute(context.Background(), job, 1); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("error = %v, want ErrStateMismatch", err)
+	}
+}
+
+// A journal that cannot be written fails the run even when every task
+// succeeds.
+func TestExecuteJournalError(t *testing.T) {
+	a, _ := model.NewTask("a", 1, nil)
+	_, err := NewExecutor().WithJournal(NewJournal(failingWriter{})).Execute(context.Background(), jobOf(t, a), 1)
+	if err == nil || !strings.Contains(err.Error(), "writing run state: disk full") {
+		t.Errorf("error = %v, want the journal's write error", err)
+	}
+
+	// A failed run reports both errors.
+	requireShell(t)
+	_, err = NewExecutor().WithJournal(NewJournal(failingWriter{})).
+		Execute(context.Background(), jobOf(t, shellTask(t, "b", nil, "exit 1")), 1)
+	var runErr *RunError
+	if !errors.As(err, &runErr) || !strings.Contains(err.Error(), "also failed writing run state: disk full") {
+		t.Errorf("error = %v, want the task failure and the journal's write error", err)
+	}
+}
+
+// Tasks only run on pool workers that have the tags they require.
+func TestExecuteTags(t *testing.T) {
+	requireShell(t)
+	train := shellTask(t, "train", nil, "sleep 0.02")
+	train.Requires = []string{"gpu"}
+	eval := shellTask(t, "eval", nil, "sleep 0.02")
+	eval.Requires = []string{"gpu"}
+	job := jobOf(t, train, eval, shellTask(t, "fetch", nil, "true"))
+	job.Pool = []model.Worker{{ID: 1, Speed: 1}, {ID: 2, Speed: 1, Tags: []string{"gpu"}}}
+	run, err := NewExecutor().Execute(context.Background(), job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ts := range run.Result.TaskSchedules {
+		if len(job.Tasks[ts.TaskID].Requires) > 0 && ts.WorkerID != 2 {
+			t.Errorf("%s ran on worker %d, want the gpu worker 2", ts.TaskID, ts.WorkerID)
+		}
+	}
+}
+
+func TestFreeWorker(t *testing.T) {
+	pool := []model.Worker{
+		{ID: 1, Speed: 1, Tags: []string{"gpu", "ssd"}},
+		{ID: 2, Speed: 1, Tags: []string{"gpu"}},
+		{ID: 3, Speed: 1},
+	}
+	plain := &model.Task{ID: "plain"}
+	gpu := &model.Task{ID: "gpu", Requires: []string{"gpu"}}
+	tests := []struct {
+		name string
+		task *model.Task
+		free []bool
+		want int
+	}{
+		{name: "untagged worker first", task: plain, free: []bool{true, true, true}, want: 2},
+		{name: "fewest tags", task: plain, free: []bool{true, true, false}, want: 1},
+		{name: "eligible only", task: gpu, free: []bool{true, true, true}, want: 1},
+		{name: "none eligible", task: gpu, free: []bool{false, false, true}, want: -1},
+	}
+	for _, tt := range tests {
+		if got := freeWorker(pool, tt.free, tt.task); got != tt.want {
+			t.Errorf("%s: freeWorker = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteMissingProgram(t *testing.T) {
+	task, _ := model.NewTask("a", 1, nil)
+	task.Command = &model.Command{Args: []string{"no-such-program-for-the-executor-test"}}
+	_, err := NewExecutor().Execute(context.Background(), jobOf(t, task), 1)
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.ExitCode != -1 {
+		t.Errorf("error = %v, want a TaskError with exit code -1", err)
+	}
+}
+
+func TestExecuteCancel(t *testing.T) {
+	requireShell(t)
+	job := jobOf(t, shellTask(t, "slow", nil, "exec sleep 5"))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewExecutor().Execute(ctx, job, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("cancelled run took %s", elapsed)
+	}
+}
+
+func TestExecuteCycle(t *testing.T) {
+	a, _ := model.NewTask("a", 1, []string{"b"})
+	b, _ := model.NewTask("b", 1, []string{"a"})
+	if _, err := NewExecutor().Execute(context.Background(), jobOf(t, a, b), 1); err == nil ||
+		!strings.Contains(err.Error(), "cycle") {
+		t.Errorf("error = %v, want a cycle error", err)
+	}
+}
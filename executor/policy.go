@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailurePolicy decides what happens to the rest of a run once a task has
+// failed, i.e. exhausted its retries.
+type FailurePolicy string
+
+// Failure policies.
+const (
+	// PolicyFailFast kills the running tasks and starts no new ones; they
+	// are reported as cancelled and skipped.
+	PolicyFailFast FailurePolicy = "fail-fast"
+	// PolicyContinue skips every task that depends on the failed one,
+	// directly or not, and keeps running the independent branches.
+	PolicyContinue FailurePolicy = "continue"
+)
+
+// FailurePolicyNames lists the accepted policy names.
+func FailurePolicyNames() []string {
+	return []string{string(PolicyFailFast), string(PolicyContinue)}
+}
+
+// ParseFailurePolicy returns the policy with the given name.
+func ParseFailurePolicy(name string) (FailurePolicy, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	for _, known := range FailurePolicyNames() {
+		if normalized == known {
+			return FailurePolicy(known), nil
+		}
+	}
+	return "", fmt.Errorf("unknown failure policy '%s' (available: %s)",
+		name, strings.Join(FailurePolicyNames(), ", "))
+}
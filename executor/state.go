@@ -0,0 +1,200 @@
+package executor
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"wingie_case/model"
+)
+
+// ErrStateMismatch is returned when a state file was written for a job with
+// a different fingerprint than the one being resumed.
+var ErrStateMismatch = errors.New("job does not match the recorded run")
+
+// Journal event types.
+const (
+	eventRun  = "run"  // a run (or resumed run) began
+	eventTask = "task" // a task changed state
+)
+
+// statusStarted marks a task that began running; it only appears in the
+// journal, the report uses the final model.TaskStatus.
+const statusStarted = "started"
+
+// journalRecord is one line of a state file.
+type journalRecord struct {
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	Job         string    `json:"job,omitempty"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	Workers     int       `json:"workers,omitempty"`
+	Task        string    `json:"task,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Attempts    int       `json:"attempts,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Journal writes the state transitions of a run as JSON lines, one record
+// per line, so that an interrupted run can be resumed (see ReadState). Each
+// record is written with a single Write call; pass an unbuffered file so
+// that it is on disk before the next task starts.
+type Journal struct {
+	w   io.Writer
+	err error
+}
+
+// NewJournal creates a Journal that appends records to w.
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{w: w}
+}
+
+// Err returns the first write error, if any; later records are dropped.
+func (j *Journal) Err() error {
+	if j == nil {
+		return nil
+	}
+	return j.err
+}
+
+func (j *Journal) write(rec journalRecord) {
+	if j == nil || j.err != nil {
+		return
+	}
+	rec.Time = time.Now().UTC()
+	line, err := json.Marshal(rec)
+	if err == nil {
+		_, err = j.w.Write(append(line, '\n'))
+	}
+	j.err = err
+}
+
+func (j *Journal) begin(job *model.Job, workers int) {
+	if j == nil {
+		return
+	}
+	j.write(journalRecord{Event: eventRun, Job: job.Name, Fingerprint: Fingerprint(job), Workers: workers})
+}
+
+func (j *Journal) task(id, status string, attempts int, err *TaskError) {
+	rec := journalRecord{Event: eventTask, Task: id, Status: status, Attempts: attempts}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	j.write(rec)
+}
+
+// State is what a state file says about the earlier runs of a job.
+type State struct {
+	Job         string
+	Fingerprint string
+	// Succeeded lists the tasks whose last recorded state is succeeded.
+	Succeeded map[string]bool
+}
+
+// ReadState rebuilds the state of a run from its journal. The last record
+// of each task wins, so a file holding a run and its resumptions yields the
+// combined state. A truncated last line, left by a crash, is ignored.
+func ReadState(r io.Reader) (*State, error) {
+	state := &State{Succeeded: map[string]bool{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var broken error
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if broken != nil {
+			return nil, broken
+		}
+		var rec journalRecord
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			broken = fmt.Errorf("line %d: %v", n, err)
+			continue
+		}
+		switch rec.Event {
+		case eventRun:
+			if state.Fingerprint != "" && rec.Fingerprint != state.Fingerprint {
+				return nil, fmt.Errorf("line %d: %w", n, ErrStateMismatch)
+			}
+			state.Job, state.Fingerprint = rec.Job, rec.Fingerprint
+		case eventTask:
+			if state.Fingerprint == "" {
+				return nil, fmt.Errorf("line %d: task record before the run record", n)
+			}
+			state.Succeeded[rec.Task] = rec.Status == string(model.StatusSucceeded)
+		default:
+			return nil, fmt.Errorf("line %d: unknown event '%s'", n, rec.Event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if state.Fingerprint == "" {
+		return nil, fmt.Errorf("no run recorded")
+	}
+	for id, ok := range state.Succeeded {
+		if !ok {
+			delete(state.Succeeded, id)
+		}
+	}
+	return state, nil
+}
+
+// Check returns an error wrapping ErrStateMismatch unless the state was
+// recorded for job.
+func (s *State) Check(job *model.Job) error {
+	if s.Fingerprint != Fingerprint(job) {
+		return fmt.Errorf("%w: it was recorded for job '%s' with fingerprint %.12s, this one has %.12s",
+			ErrStateMismatch, s.Job, s.Fingerprint, Fingerprint(job))
+	}
+	return nil
+}
+
+// Fingerprint identifies what a job does: its tasks, their dependencies
+// with the type and lag of typed links, and their commands. Durations,
+// retries and timeouts are left out, so refining the estimates or the
+// failure handling keeps a run resumable. Plain dependencies add nothing
+// beyond their ID, so jobs without typed links keep their fingerprint.
+func Fingerprint(job *model.Job) string {
+	ids := make([]string, 0, len(job.Tasks))
+	for id := range job.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, id := range ids {
+		task := job.Tasks[id]
+		deps := append([]string(nil), task.Dependencies...)
+		sort.Strings(deps)
+		var links []string // "dep:SS+2" for each typed link, in dependency order
+		for _, dep := range deps {
+			if link := task.Link(dep); !link.IsPlain() {
+				links = append(links, dep+":"+link.String())
+			}
+		}
+		entry := struct {
+			ID    string
+			Deps  []string
+			Links []string `json:",omitempty"`
+			Args  []string `json:",omitempty"`
+			Env   []string `json:",omitempty"`
+			Dir   string   `json:",omitempty"`
+		}{ID: id, Deps: deps, Links: links}
+		if c := task.Command; c != nil {
+			entry.Args, entry.Env, entry.Dir = c.Args, c.Env, c.Dir
+		}
+		enc.Encode(entry)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,199 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+// journalText returns the records as a state file.
+func journalText(t *testing.T, records ...journalRecord) string {
+	t.Helper()
+	var b strings.Builder
+	for _, rec := range records {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestReadState(t *testing.T) {
+	run := journalRecord{Event: eventRun, Job: "J", Fingerprint: "f1", Workers: 2}
+	task := func(id string, status model.TaskStatus) journalRecord {
+		return journalRecord{Event: eventTask, Task: id, Status: string(status)}
+	}
+	started := journalRecord{Event: eventTask, Task: "b", Status: statusStarted}
+	complete := journalText(t, run, task("a", model.StatusSucceeded), started)
+
+	tests := []struct {
+		name      string
+		text      string
+		succeeded []string
+		wantErr   string
+	}{
+		{
+			name:      "complete",
+			text:      complete,
+			succeeded: []string{"a"},
+		},
+		{
+			name:      "truncated last line",
+			text:      complete + `{"event":"task","task":"b","sta`,
+			succeeded: []string{"a"},
+		},
+		{
+			name:      "truncated last line with newline",
+			text:      complete + "{\"event\":\"ta\n\n",
+			succeeded: []string{"a"},
+		},
+		{
+			name:    "broken line in the middle",
+			text:    journalText(t, run) + "{\"event\":\n" + journalText(t, task("a", model.StatusSucceeded)),
+			wantErr: "line 2",
+		},
+		{
+			// Reused tasks are not recorded again.
+			name:      "resumed run",
+			text:      complete + journalText(t, run, task("b", model.StatusSucceeded)),
+			succeeded: []string{"a", "b"},
+		},
+		{
+			name:      "last record wins",
+			text:      journalText(t, run, task("a", model.StatusSucceeded), task("b", model.StatusSucceeded), task("b", model.StatusFailed)),
+			succeeded: []string{"a"},
+		},
+		{
+			name:    "other job",
+			text:    complete + journalText(t, journalRecord{Event: eventRun, Job: "J", Fingerprint: "f2"}),
+			wantErr: "line 4: job does not match",
+		},
+		{
+			name:    "task before run",
+			text:    journalText(t, task("a", model.StatusSucceeded)),
+			wantErr: "line 1: task record before the run record",
+		},
+		{
+			name:    "unknown event",
+			text:    journalText(t, run, journalRecord{Event: "pause"}),
+			wantErr: "line 2: unknown event 'pause'",
+		},
+		{
+			name:    "empty",
+			text:    "",
+			wantErr: "no run recorded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := ReadState(strings.NewReader(tt.text))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.Job != "J" || state.Fingerprint != "f1" {
+				t.Errorf("job %q with fingerprint %q, want J and f1", state.Job, state.Fingerprint)
+			}
+			if len(state.Succeeded) != len(tt.succeeded) {
+				t.Errorf("succeeded %v, want %v", state.Succeeded, tt.succeeded)
+			}
+			for _, id := range tt.succeeded {
+				if !state.Succeeded[id] {
+					t.Errorf("succeeded %v, want %v", state.Succeeded, tt.succeeded)
+				}
+			}
+		})
+	}
+}
+
+func TestReadStateMismatch(t *testing.T) {
+	text := journalText(t,
+		journalRecord{Event: eventRun, Fingerprint: "f1"},
+		journalRecord{Event: eventRun, Fingerprint: "f2"},
+	)
+	if _, err := ReadState(strings.NewReader(text)); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("error = %v, want ErrStateMismatch", err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := func() *model.Job {
+		job := model.NewJob("J")
+		a, _ := model.NewTask("a", 3, nil)
+		a.Command = &model.Command{Args: []string{"make", "a"}}
+		b, _ := model.NewTask("b", 2, []string{"a"})
+		job.AddTask(a)
+		job.AddTask(b)
+		return job
+	}
+	want := Fingerprint(base())
+
+	tests := []struct {
+		name    string
+		change  func(job *model.Job)
+		changed bool
+	}{
+		{name: "duration", change: func(job *model.Job) { job.Tasks["a"].Duration = 9 }},
+		{name: "retries", change: func(job *model.Job) { job.Tasks["a"].Command.Retries = 3 }},
+		{name: "name", change: func(job *model.Job) { job.Name = "K" }},
+		{name: "explicit FS link", change: func(job *model.Job) {
+			job.Tasks["b"].Links = map[string]model.Link{"a": {Type: model.FinishToStart}}
+		}},
+		{name: "arguments", changed: true, change: func(job *model.Job) { job.Tasks["a"].Command.Args = []string{"make", "b"} }},
+		{name: "dependency", changed: true, change: func(job *model.Job) { job.Tasks["b"].Dependencies = nil }},
+		{name: "link type", changed: true, change: func(job *model.Job) {
+			job.Tasks["b"].Links = map[string]model.Link{"a": {Type: model.StartToStart}}
+		}},
+		{name: "lag", changed: true, change: func(job *model.Job) {
+			job.Tasks["b"].Links = map[string]model.Link{"a": {Type: model.FinishToStart, Lag: -1}}
+		}},
+	}
+	for _, tt := range tests {
+		job := base()
+		tt.change(job)
+		if got := Fingerprint(job); (got != want) != tt.changed {
+			t.Errorf("%s: fingerprint changed %v, want %v", tt.name, got != want, tt.changed)
+		}
+	}
+
+	// SS+1 and SS+2 differ, as do SS and FF with the same lag.
+	seen := map[string]string{}
+	for _, link := range []string{"SS+1", "SS+2", "FF+1", "SF-1", "FS-1"} {
+		job := base()
+		l, _ := model.ParseLink(link)
+		job.Tasks["b"].Links = map[string]model.Link{"a": l}
+		fp := Fingerprint(job)
+		if other, dup := seen[fp]; dup {
+			t.Errorf("links %s and %s have the same fingerprint", other, link)
+		}
+		seen[fp] = link
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestJournalErr(t *testing.T) {
+	j := NewJournal(failingWriter{})
+	j.begin(model.NewJob("J"), 1)
+	j.task("a", statusStarted, 0, nil)
+	if err := j.Err(); err == nil || err.Error() != "disk full" {
+		t.Errorf("Err() = %v, want the first write error", err)
+	}
+	var none *Journal
+	if err := none.Err(); err != nil {
+		t.Errorf("nil journal: Err() = %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"wingie_case/model"
+)
+
+// jobDefinition is the format-independent shape of a job file.
+// File readers decode into it and call build to obtain a JobInput. Tasks is
+// left to each reader, which decodes the entries one by one so that errors
+// can name the offending index.
+type jobDefinition struct {
+	Name      string             `json:"name" yaml:"name" toml:"name"`
+	Workers   *int               `json:"workers" yaml:"workers" toml:"workers"` // nil when the file does not set a worker count
+	Pool      []workerDefinition `json:"pool" yaml:"pool" toml:"pool"`
+	Resources map[string]int     `json:"resources" yaml:"resources" toml:"resources"` // capacity per resource name
+	Tasks     []taskDefinition   `json:"-" yaml:"-" toml:"-"`
+}
+
+// workerDefinition is one entry of the optional "pool" of workers. The same
+// struct is decoded by every structured format, hence the tags for each of
+// them. Speed defaults to 1 when omitted; Tags are the capabilities tasks
+// can require.
+type workerDefinition struct {
+	Name  string   `json:"name" yaml:"name" toml:"name"`
+	Speed *float64 `json:"speed" yaml:"speed" toml:"speed"`
+	Tags  []string `json:"tags" yaml:"tags" toml:"tags"`
+}
+
+// taskDefinition describes one entry of the task list in a job file. Like
+// the other definitions it is decoded by every structured format.
+type taskDefinition struct {
+	ID           string                 `json:"id" yaml:"id" toml:"id"`
+	Duration     int                    `json:"duration" yaml:"duration" toml:"duration"`
+	Dependencies []dependencyDefinition `json:"dependencies" yaml:"dependencies" toml:"dependencies"`
+	Command      *commandDefinition     `json:"command" yaml:"command" toml:"command"`
+	Requires     []string               `json:"requires" yaml:"requires" toml:"requires"`
+	Resources    map[string]int         `json:"resources" yaml:"resources" toml:"resources"` // demand per resource name
+}
+
+// dependencyDefinition is one entry of a task's dependencies: a task ID, or
+// an object giving the ID, the link type (FS, SS, FF or SF; FS when omitted)
+// and a lag in time units, which may be negative. Each structured format
+// decodes both forms (see dependency.go).
+type dependencyDefinition struct {
+	ID   string `json:"id" yaml:"id" toml:"id"`
+	Type string `json:"type" yaml:"type" toml:"type"`
+	Lag  int    `json:"lag" yaml:"lag" toml:"lag"`
+}
+
+// commandDefinition is the optional "command" of a task. The same struct is
+// decoded by every structured format, hence the tags for each of them.
+// Backoff and Timeout are Go duration strings such as "500ms" or "2m".
+type commandDefinition struct {
+	Args    []string          `json:"args" yaml:"args" toml:"args"`
+	Env     map[string]string `json:"env" yaml:"env" toml:"env"`
+	Dir     string            `json:"dir" yaml:"dir" toml:"dir"`
+	Retries int               `json:"retries" yaml:"retries" toml:"retries"`
+	Backoff string            `json:"backoff" yaml:"backoff" toml:"backoff"`
+	Timeout string            `json:"timeout" yaml:"timeout" toml:"timeout"`
+}
+
+// build converts the definition into a JobInput.
+// Errors name the offending field, e.g. "tasks[2].duration".
+// When the worker count is omitted it defaults to the size of the pool, or
+// to the number of tasks when there is no pool.
+func (d *jobDefinition) build() (*JobInput, error) {
+	if len(d.Tasks) == 0 {
+		return nil, fmt.Errorf("tasks: at least one task is required")
+	}
+
+	job := model.NewJob(strings.TrimSpace(d.Name))
+	for i, td := range d.Tasks {
+		task, err := td.build(i)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := job.Tasks[task.ID]; exists {
+			return nil, fmt.Errorf("tasks[%d].id: duplicate task ID '%s'", i, task.ID)
+		}
+		if err := job.AddTask(task); err != nil {
+			return nil, fmt.Errorf("tasks[%d]: %w", i, err)
+		}
+	}
+
+	pool, err := d.buildPool()
+	if err != nil {
+		return nil, err
+	}
+	job.Pool = pool
+	if job.Resources, err = buildAmounts(d.Resources, "resources", "capacity"); err != nil {
+		return nil, err
+	}
+	workers := job.TaskCount()
+	if len(job.Pool) > 0 {
+		workers = len(job.Pool)
+		if d.Workers != nil && *d.Workers != workers {
+			return nil, fmt.Errorf("workers: worker count %d does not match the pool of %d worker(s)", *d.Workers, workers)
+		}
+	}
+	if d.Workers != nil {
+		workers = *d.Workers
+		if workers <= 0 {
+			return nil, fmt.Errorf("workers: worker count must be positive, got %d", workers)
+		}
+	}
+
+	return &JobInput{Job: job, Workers: workers}, nil
+}
+
+// buildPool converts the worker pool; worker IDs follow the list order.
+func (d *jobDefinition) buildPool() ([]model.Worker, error) {
+	if len(d.Pool) == 0 {
+		return nil, nil
+	}
+	pool := make([]model.Worker, 0, len(d.Pool))
+	names := make(map[string]bool, len(d.Pool))
+	for i, wd := range d.Pool {
+		speed := 1.0
+		if wd.Speed != nil {
+			speed = *wd.Speed
+		}
+		worker, err := model.NewWorker(i+1, strings.TrimSpace(wd.Name), speed)
+		if err != nil {
+			return nil, fmt.Errorf("pool[%d].speed: speed must be a positive number, got %g", i, speed)
+		}
+		if names[worker.Name] {
+			return nil, fmt.Errorf("pool[%d].name: duplicate worker name '%s'", i, worker.Name)
+		}
+		if worker.Tags, err = buildTags(wd.Tags, fmt.Sprintf("pool[%d].tags", i)); err != nil {
+			return nil, err
+		}
+		names[worker.Name] = true
+		pool = append(pool, worker)
+	}
+	return pool, nil
+}
+
+// build converts a single task entry; index is its position in the task list.
+func (td *taskDefinition) build(index int) (*model.Task, error) {
+	id := strings.TrimSpace(td.ID)
+	if id == "" {
+		return nil, fmt.Errorf("tasks[%d].id: task ID cannot be empty", index)
+	}
+	if td.Duration <= 0 {
+		return nil, fmt.Errorf("tasks[%d].duration: duration for task '%s' must be positive, got %d",
+			index, id, td.Duration)
+	}
+
+	deps := make([]string, 0, len(td.Dependencies))
+	seen := make(map[string]bool, len(td.Dependencies))
+	var links map[string]model.Link
+	for j, dd := range td.Dependencies {
+		dep := strings.TrimSpace(dd.ID)
+		if dep == "" {
+			return nil, fmt.Errorf("tasks[%d].dependencies[%d]: dependency ID cannot be empty", index, j)
+		}
+		if seen[dep] {
+			return nil, fmt.Errorf("tasks[%d].dependencies[%d]: duplicate dependency '%s'", index, j, dep)
+		}
+		seen[dep] = true
+		deps = append(deps, dep)
+
+		depType, err := model.ParseDependencyType(dd.Type)
+		if err != nil {
+			return nil, fmt.Errorf("tasks[%d].dependencies[%d].type: %v", index, j, err)
+		}
+		if link := (model.Link{Type: depType, Lag: dd.Lag}); !link.IsPlain() {
+			if links == nil {
+				links = make(map[string]model.Link)
+			}
+			links[dep] = link
+		}
+	}
+
+	task, err := model.NewTask(id, td.Duration, deps)
+	if err != nil {
+		return nil, err
+	}
+	task.Links = links
+	if task.Requires, err = buildTags(td.Requires, fmt.Sprintf("tasks[%d].requires", index)); err != nil {
+		return nil, err
+	}
+	if task.Resources, err = buildAmounts(td.Resources, fmt.Sprintf("tasks[%d].resources", index), "demand"); err != nil {
+		return nil, err
+	}
+	if td.Command != nil {
+		if task.Command, err = td.Command.build(index); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// buildTags trims a list of worker tags; field names the list in errors.
+// Empty and repeated tags are rejected.
+func buildTags(raw []string, field string) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for j, r := range raw {
+		tag := strings.TrimSpace(r)
+		if tag == "" {
+			return nil, fmt.Errorf("%s[%d]: tag cannot be empty", field, j)
+		}
+		if seen[tag] {
+			return nil, fmt.Errorf("%s[%d]: duplicate tag '%s'", field, j, tag)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// buildAmounts trims the names of resource capacities or demands; field names
+// the map in errors and kind the amounts ("capacity", "demand"). Names must
+// be unique after trimming and amounts positive.
+func buildAmounts(raw map[string]int, field, kind string) (map[string]int, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	amounts := make(map[string]int, len(raw))
+	for _, key := range keys {
+		name := strings.TrimSpace(key)
+		if name == "" {
+			return nil, fmt.Errorf("%s: resource name cannot be empty", field)
+		}
+		if _, dup := amounts[name]; dup {
+			return nil, fmt.Errorf("%s.%s: duplicate resource name", field, name)
+		}
+		if raw[key] <= 0 {
+			return nil, fmt.Errorf("%s.%s: %s must be positive, got %d", field, name, kind, raw[key])
+		}
+		amounts[name] = raw[key]
+	}
+	return amounts, nil
+}
+
+// maxRetries is the largest retry count a command may ask for.
+const maxRetries = 100
+
+// build converts the command of the task at index. Environment entries are
+// sorted by key so that runs are reproducible.
+func (cd *commandDefinition) build(index int) (*model.Command, error) {
+	if len(cd.Args) == 0 || strings.TrimSpace(cd.Args[0]) == "" {
+		return nil, fmt.Errorf("tasks[%d].command.args: a program name is required", index)
+	}
+	keys := make([]string, 0, len(cd.Env))
+	for key := range cd.Env {
+		if key == "" || strings.Contains(key, "=") {
+			return nil, fmt.Errorf("tasks[%d].command.env: invalid variable name '%s'", index, key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, key+"="+cd.Env[key])
+	}
+	if cd.Retries < 0 || cd.Retries > maxRetries {
+		return nil, fmt.Errorf("tasks[%d].command.retries: retry count must be between 0 and %d, got %d",
+			index, maxRetries, cd.Retries)
+	}
+	backoff, err := parseCommandDuration(cd.Backoff, index, "backoff")
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := parseCommandDuration(cd.Timeout, index, "timeout")
+	if err != nil {
+		return nil, err
+	}
+	return &model.Command{
+		Args:    cd.Args,
+		Env:     env,
+		Dir:     cd.Dir,
+		Retries: cd.Retries,
+		Backoff: backoff,
+		Timeout: timeout,
+	}, nil
+}
+
+// parseCommandDuration parses an optional, non-negative duration of the
+// command of the task at index; field names it in errors.
+func parseCommandDuration(raw string, index int, field string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("tasks[%d].command.%s: invalid duration '%s', use e.g. \"500ms\" or \"2m\"", index, field, raw)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("tasks[%d].command.%s: duration cannot be negative, got %s", index, field, raw)
+	}
+	return d, nil
+}
@@ -0,0 +1,92 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dependencyFields lists the keys of the object form of a dependency.
+var dependencyFields = map[string]bool{"id": true, "type": true, "lag": true}
+
+// UnmarshalJSON accepts a task ID string or an object with id, type and lag.
+func (d *dependencyDefinition) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '"':
+		*d = dependencyDefinition{}
+		return json.Unmarshal(data, &d.ID)
+	case len(data) > 0 && data[0] == '{':
+		type object dependencyDefinition // without the UnmarshalJSON method
+		var o object
+		if err := decodeStrict(data, &o); err != nil {
+			return fmt.Errorf("dependency: %v", err)
+		}
+		*d = dependencyDefinition(o)
+		return nil
+	}
+	return fmt.Errorf("dependency must be a task ID or an object with \"id\", \"type\" and \"lag\", got %s", data)
+}
+
+// UnmarshalYAML accepts a task ID scalar or a mapping with id, type and lag.
+func (d *dependencyDefinition) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*d = dependencyDefinition{}
+		return node.Decode(&d.ID)
+	case yaml.MappingNode:
+		for k := 0; k+1 < len(node.Content); k += 2 {
+			if key := node.Content[k]; !dependencyFields[key.Value] {
+				return fmt.Errorf("line %d: unknown dependency field %q", key.Line, key.Value)
+			}
+		}
+		type object dependencyDefinition // without the UnmarshalYAML method
+		var o object
+		if err := node.Decode(&o); err != nil {
+			return err
+		}
+		*d = dependencyDefinition(o)
+		return nil
+	}
+	return fmt.Errorf("line %d: dependency must be a task ID or a mapping with id, type and lag", node.Line)
+}
+
+// UnmarshalTOML accepts a task ID string or an inline table with id, type
+// and lag.
+func (d *dependencyDefinition) UnmarshalTOML(value any) error {
+	*d = dependencyDefinition{}
+	switch v := value.(type) {
+	case string:
+		d.ID = v
+		return nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			var ok bool
+			switch key {
+			case "id":
+				d.ID, ok = v[key].(string)
+			case "type":
+				d.Type, ok = v[key].(string)
+			case "lag":
+				var lag int64
+				lag, ok = v[key].(int64)
+				d.Lag = int(lag)
+			default:
+				return fmt.Errorf("unknown dependency field %q", key)
+			}
+			if !ok {
+				return fmt.Errorf("dependency field %q has the wrong type (%T)", key, v[key])
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("dependency must be a task ID or a table with id, type and lag, got %T", value)
+}
@@ -0,0 +1,105 @@
+package input
+
+import (
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+// Each structured format accepts a dependency as a plain ID or as an object
+// with a link type and lag.
+func TestDependencyForms(t *testing.T) {
+	sources := map[Format]string{
+		FormatJSON: `{"tasks": [
+			{"id": "a", "duration": 2},
+			{"id": "b", "duration": 1, "dependencies": ["a"]},
+			{"id": "c", "duration": 1, "dependencies": [{"id": "a", "type": "ss", "lag": -1}, {"id": "b"}]}
+		]}`,
+		FormatYAML: `tasks:
+  - {id: a, duration: 2}
+  - {id: b, duration: 1, dependencies: [a]}
+  - id: c
+    duration: 1
+    dependencies:
+      - {id: a, type: SS, lag: -1}
+      - id: b
+`,
+		FormatTOML: `[[tasks]]
+id = "a"
+duration = 2
+
+[[tasks]]
+id = "b"
+duration = 1
+dependencies = ["a"]
+
+[[tasks]]
+id = "c"
+duration = 1
+dependencies = [{id = "a", type = "SS", lag = -1}, {id = "b"}]
+`,
+	}
+	for format, src := range sources {
+		t.Run(string(format), func(t *testing.T) {
+			in, err := NewFileReaderFrom(strings.NewReader(src), format).ReadJob()
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := in.Job.Tasks["c"]
+			if got := strings.Join(c.Dependencies, ","); got != "a,b" {
+				t.Errorf("c depends on %s, want a,b", got)
+			}
+			if got := c.Link("a"); got != (model.Link{Type: model.StartToStart, Lag: -1}) {
+				t.Errorf("link from a is %v, want SS-1", got)
+			}
+			if got := c.Link("b"); !got.IsPlain() {
+				t.Errorf("link from b is %v, want a plain dependency", got)
+			}
+			if len(in.Job.Tasks["b"].Links) != 0 {
+				t.Errorf("b has links %v", in.Job.Tasks["b"].Links)
+			}
+		})
+	}
+}
+
+func TestDependencyErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		src    string
+		want   string
+	}{
+		{name: "json unknown type", format: FormatJSON, src: `{"tasks": [{"id": "a", "duration": 1}, {"id": "b", "duration": 1, "dependencies": [{"id": "a", "type": "SX"}]}]}`, want: "tasks[1].dependencies[0].type"},
+		{name: "json unknown field", format: FormatJSON, src: `{"tasks": [{"id": "a", "duration": 1}, {"id": "b", "duration": 1, "dependencies": [{"id": "a", "lead": 1}]}]}`, want: "lead"},
+		{name: "json number", format: FormatJSON, src: `{"tasks": [{"id": "a", "duration": 1, "dependencies": [1]}]}`, want: "dependency must be a task ID or an object"},
+		{name: "yaml unknown field", format: FormatYAML, src: "tasks:\n  - id: a\n    duration: 1\n  - id: b\n    duration: 1\n    dependencies: [{id: a, lead: 1}]\n", want: `unknown dependency field "lead"`},
+		{name: "yaml sequence", format: FormatYAML, src: "tasks:\n  - id: a\n    duration: 1\n    dependencies: [[b]]\n", want: "dependency must be a task ID or a mapping"},
+		{name: "toml unknown field", format: FormatTOML, src: "[[tasks]]\nid = \"a\"\nduration = 1\ndependencies = [{id = \"b\", lead = 1}]\n", want: `unknown dependency field "lead"`},
+		{name: "toml wrong type", format: FormatTOML, src: "[[tasks]]\nid = \"a\"\nduration = 1\ndependencies = [{id = \"b\", lag = \"1\"}]\n", want: `dependency field "lag" has the wrong type`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileReaderFrom(strings.NewReader(tt.src), tt.format).ReadJob()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// The interactive prompts take a link after a colon.
+func TestCLIReaderLinks(t *testing.T) {
+	in, err := NewCLIReader(strings.NewReader("J\n2\na\n2\n\nb\n1\na:SS-1\n1\n")).ReadJob()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := in.Job.Tasks["b"].Link("a"); got != (model.Link{Type: model.StartToStart, Lag: -1}) {
+		t.Errorf("link from a is %v, want SS-1", got)
+	}
+
+	_, err = NewCLIReader(strings.NewReader("J\n1\na\n2\nb:SX\n")).ReadJob()
+	if err == nil || !strings.Contains(err.Error(), "dependency 'b': invalid link 'SX'") {
+		t.Errorf("error = %v, want an invalid link", err)
+	}
+}
@@ -0,0 +1,412 @@
+package input
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// DOTReader reads a job from a Graphviz digraph:
+//
+//	digraph J {
+//	  workers=2;
+//	  A [duration=3];
+//	  B [label="B (2)"];
+//	  A -> D -> F;
+//	  D [duration=5]; F [duration=3];
+//	}
+//
+// Node IDs are task IDs and an edge A -> D makes D depend on A; an edge
+// label in link notation, as in A -> D [label="SS+2"], types the link and
+// sets its lag (see model.ParseLink). A node's
+// duration is its "duration" attribute, or else the first "(N)" in its
+// label, or else the default from "node [duration=N]". The graph ID (or a
+// "name" attribute) is the job name and a "workers" attribute sets the
+// worker count. Subgraphs, ports and HTML labels are not supported; other
+// attributes are ignored, so the output of DOTPrinter reads back unchanged.
+type DOTReader struct {
+	r    io.Reader
+	path string
+}
+
+// NewDOTReader creates a DOTReader that decodes from r.
+func NewDOTReader(r io.Reader) *DOTReader {
+	return &DOTReader{r: r}
+}
+
+// NewDOTFileReader creates a DOTReader that decodes the file at path.
+// The file is opened when ReadJob is called.
+func NewDOTFileReader(path string) *DOTReader {
+	return &DOTReader{path: path}
+}
+
+// ReadJob parses the digraph and converts it into a JobInput.
+func (d *DOTReader) ReadJob() (*JobInput, error) {
+	return readJobWith(d.r, d.path, decodeDOTJob)
+}
+
+func decodeDOTJob(data []byte) (*JobInput, error) {
+	tokens, err := tokenizeDOT(string(data))
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{tokens: tokens, graph: newGraphSource(), defaults: map[string]string{}}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	def, err := p.graph.definition()
+	if err != nil {
+		return nil, err
+	}
+	return def.build()
+}
+
+type dotToken struct {
+	text   string
+	quoted bool // a "..." string, never a keyword or punctuation
+	line   int
+}
+
+// tokenizeDOT splits a DOT document into IDs, quoted strings and the
+// punctuation { } [ ] ; , = -> --. Comments (//, /* */ and lines starting
+// with #) are skipped.
+func tokenizeDOT(src string) ([]dotToken, error) {
+	var tokens []dotToken
+	line := 1
+	atLineStart := true
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+			atLineStart = true
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '#' && atLineStart:
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		}
+		atLineStart = false
+
+		switch {
+		case strings.HasPrefix(src[i:], "->"), strings.HasPrefix(src[i:], "--"):
+			tokens = append(tokens, dotToken{text: src[i : i+2], line: line})
+			i += 2
+		case strings.ContainsRune("{}[];,=", rune(c)):
+			tokens = append(tokens, dotToken{text: string(c), line: line})
+			i++
+		case c == '"':
+			var b strings.Builder
+			start := line
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+					switch src[j] {
+					case 'n', 'l', 'r':
+						b.WriteByte('\n')
+					case '\n':
+						line++ // line continuation
+					default:
+						b.WriteByte(src[j])
+					}
+					continue
+				}
+				if src[j] == '\n' {
+					line++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", start)
+			}
+			tokens = append(tokens, dotToken{text: b.String(), quoted: true, line: start})
+			i = j + 1
+		case isDOTIDChar(rune(c)):
+			j := i
+			for j < len(src) && isDOTIDChar(rune(src[j])) {
+				j++
+			}
+			tokens = append(tokens, dotToken{text: src[i:j], line: line})
+			i = j
+		case c == '<':
+			return nil, fmt.Errorf("line %d: HTML labels are not supported", line)
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, c)
+		}
+	}
+	return tokens, nil
+}
+
+func isDOTIDChar(r rune) bool {
+	return r == '_' || r == '.' || r >= 0x80 || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// dotParser walks the token list; graph collects what it finds.
+type dotParser struct {
+	tokens   []dotToken
+	pos      int
+	graph    *graphSource
+	defaults map[string]string // node [...] attributes
+}
+
+func (p *dotParser) peek() (dotToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return dotToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// is reports whether the next token is the unquoted text s.
+func (p *dotParser) is(s string) bool {
+	t, ok := p.peek()
+	return ok && !t.quoted && t.text == s
+}
+
+func (p *dotParser) line() int {
+	if t, ok := p.peek(); ok {
+		return t.line
+	}
+	if len(p.tokens) > 0 {
+		return p.tokens[len(p.tokens)-1].line
+	}
+	return 1
+}
+
+func (p *dotParser) expect(s string) error {
+	if !p.is(s) {
+		return p.unexpected("'" + s + "'")
+	}
+	p.pos++
+	return nil
+}
+
+func (p *dotParser) unexpected(want string) error {
+	t, ok := p.peek()
+	if !ok {
+		return fmt.Errorf("line %d: expected %s, got end of input", p.line(), want)
+	}
+	return fmt.Errorf("line %d: expected %s, got '%s'", t.line, want, t.text)
+}
+
+// id consumes an ID or quoted string.
+func (p *dotParser) id() (string, error) {
+	t, ok := p.peek()
+	if !ok || !t.quoted && !isDOTIDChar([]rune(t.text)[0]) {
+		return "", p.unexpected("an ID")
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *dotParser) parse() error {
+	if p.is("strict") {
+		p.pos++
+	}
+	if p.is("graph") {
+		return fmt.Errorf("line %d: undirected graphs are not supported, use digraph", p.line())
+	}
+	if err := p.expect("digraph"); err != nil {
+		return err
+	}
+	if !p.is("{") {
+		name, err := p.id()
+		if err != nil {
+			return err
+		}
+		p.graph.name = name
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.is("}") {
+		if _, ok := p.peek(); !ok {
+			return p.unexpected("'}'")
+		}
+		if err := p.statement(); err != nil {
+			return err
+		}
+		for p.is(";") || p.is(",") {
+			p.pos++
+		}
+	}
+	p.pos++
+	if t, ok := p.peek(); ok {
+		return fmt.Errorf("line %d: unexpected '%s' after the graph", t.line, t.text)
+	}
+	return nil
+}
+
+func (p *dotParser) statement() error {
+	line := p.line()
+	switch {
+	case p.is("subgraph") || p.is("{"):
+		return fmt.Errorf("line %d: subgraphs are not supported", line)
+	case p.is("graph"), p.is("node"), p.is("edge"):
+		kind := p.tokens[p.pos].text
+		p.pos++
+		attrs, err := p.attributes()
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case "graph":
+			return p.graphAttributes(attrs, line)
+		case "node":
+			for k, v := range attrs {
+				p.defaults[k] = v
+			}
+		}
+		return nil
+	}
+
+	first, err := p.id()
+	if err != nil {
+		return err
+	}
+	if p.is("=") {
+		p.pos++
+		value, err := p.id()
+		if err != nil {
+			return err
+		}
+		return p.graphAttributes(map[string]string{first: value}, line)
+	}
+
+	chain := []string{first}
+	for p.is("->") || p.is("--") {
+		if p.is("--") {
+			return fmt.Errorf("line %d: undirected edge '--', use '->'", p.line())
+		}
+		p.pos++
+		next, err := p.id()
+		if err != nil {
+			return err
+		}
+		chain = append(chain, next)
+	}
+	attrs, err := p.attributes()
+	if err != nil {
+		return err
+	}
+
+	if len(chain) == 1 {
+		return p.nodeAttributes(first, attrs, line)
+	}
+	link, err := edgeLink(attrs["label"], line)
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(chain); i++ {
+		p.graph.edge(chain[i-1], chain[i], link, line)
+	}
+	for _, id := range chain {
+		if err := p.nodeAttributes(id, nil, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributes parses zero or more [k=v, ...] lists.
+func (p *dotParser) attributes() (map[string]string, error) {
+	attrs := map[string]string{}
+	for p.is("[") {
+		p.pos++
+		for !p.is("]") {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			value := "true"
+			if p.is("=") {
+				p.pos++
+				if value, err = p.id(); err != nil {
+					return nil, err
+				}
+			}
+			attrs[key] = value
+			for p.is(",") || p.is(";") {
+				p.pos++
+			}
+		}
+		p.pos++
+	}
+	return attrs, nil
+}
+
+func (p *dotParser) graphAttributes(attrs map[string]string, line int) error {
+	for key, value := range attrs {
+		switch key {
+		case "workers":
+			if err := p.graph.setWorkers(value, line); err != nil {
+				return err
+			}
+		case "name":
+			p.graph.name = value
+		}
+	}
+	return nil
+}
+
+// nodeAttributes declares the node and resolves its duration from attrs,
+// its label or the node defaults. A later statement can still set the
+// duration of a node that has none yet.
+func (p *dotParser) nodeAttributes(id string, attrs map[string]string, line int) error {
+	n := p.graph.node(id, line)
+	lookup := func(key string) (string, bool) {
+		if v, ok := attrs[key]; ok {
+			return v, true
+		}
+		v, ok := p.defaults[key]
+		return v, ok
+	}
+
+	if v, ok := attrs["duration"]; ok {
+		d, err := parseDuration(id, v, line)
+		if err != nil {
+			return err
+		}
+		n.duration = d
+		return nil
+	}
+	if n.duration != 0 {
+		return nil
+	}
+	if label, ok := lookup("label"); ok {
+		if d, _, ok := durationFromLabel(label); ok {
+			if d <= 0 {
+				return fmt.Errorf("line %d: node '%s': duration must be positive, got %d", line, id, d)
+			}
+			n.duration = d
+			return nil
+		}
+	}
+	if v, ok := p.defaults["duration"]; ok {
+		d, err := parseDuration(id, v, line)
+		if err != nil {
+			return err
+		}
+		n.duration = d
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies a job file format.
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatYAML    Format = "yaml"
+	FormatTOML    Format = "toml"
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+)
+
+// decodeFunc converts raw file contents into a JobInput.
+type decodeFunc func(data []byte) (*JobInput, error)
+
+var decoders = map[Format]decodeFunc{
+	FormatJSON:    decodeJSONJob,
+	FormatYAML:    decodeYAMLJob,
+	FormatTOML:    decodeTOMLJob,
+	FormatDOT:     decodeDOTJob,
+	FormatMermaid: decodeMermaidJob,
+}
+
+// extensions maps lower-case file extensions to formats.
+var extensions = map[string]Format{
+	".json":    FormatJSON,
+	".yaml":    FormatYAML,
+	".yml":     FormatYAML,
+	".toml":    FormatTOML,
+	".dot":     FormatDOT,
+	".gv":      FormatDOT,
+	".mmd":     FormatMermaid,
+	".mermaid": FormatMermaid,
+}
+
+// ParseFormat converts a user-supplied format name (e.g. "yml") into a Format.
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if f, ok := extensions["."+name]; ok {
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown job file format '%s'", name)
+}
+
+// FormatFromPath returns the format implied by the file extension, if any.
+func FormatFromPath(path string) (Format, bool) {
+	f, ok := extensions[strings.ToLower(filepath.Ext(path))]
+	return f, ok
+}
+
+// SniffFormat guesses the format from the document contents.
+// A leading '{' means JSON, unless the document is not valid JSON but is
+// valid YAML: flow mappings such as "{name: J, tasks: [...]}" start the same
+// way. Broken JSON stays JSON so that its syntax error is reported. A
+// leading "digraph" (or "strict digraph")
+// means DOT. A "flowchart" or "graph" declaration, possibly after "%%"
+// comments and front matter, means Mermaid. A "[[tasks]]" table or a
+// top-level "key = value" line means TOML; everything else is treated as
+// YAML.
+func SniffFormat(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var node yaml.Node
+		if !json.Valid(trimmed) && yaml.Unmarshal(trimmed, &node) == nil {
+			return FormatYAML
+		}
+		return FormatJSON
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if isMermaid(lines) {
+		return FormatMermaid
+	}
+	if len(lines) == 0 {
+		return FormatYAML
+	}
+
+	first := lines[0]
+	if fields := strings.Fields(strings.TrimPrefix(first, "strict ")); len(fields) > 0 &&
+		(fields[0] == "digraph" || strings.HasPrefix(fields[0], "digraph{")) {
+		return FormatDOT
+	}
+	if strings.HasPrefix(first, "[") && strings.HasSuffix(first, "]") {
+		return FormatTOML
+	}
+	if eq := strings.Index(first, "="); eq > 0 {
+		colon := strings.Index(first, ":")
+		if colon < 0 || eq < colon {
+			return FormatTOML
+		}
+	}
+	return FormatYAML
+}
+
+// isMermaid reports whether the first line after comments and front matter
+// is a flowchart declaration.
+func isMermaid(lines []string) bool {
+	i := 0
+	if i < len(lines) && lines[i] == "---" {
+		for i++; i < len(lines) && lines[i] != "---"; i++ {
+		}
+		i++
+	}
+	for ; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "%%") {
+			return mermaidHeader.MatchString(lines[i])
+		}
+	}
+	return false
+}
+
+// FileReader reads a job definition in any supported format.
+// The format is taken from the file extension when known; otherwise it is
+// detected from the contents.
+type FileReader struct {
+	r      io.Reader
+	path   string
+	format Format
+}
+
+// NewFileReader creates a FileReader for the file at path.
+func NewFileReader(path string) *FileReader {
+	f, _ := FormatFromPath(path)
+	return &FileReader{path: path, format: f}
+}
+
+// NewFileReaderFrom creates a FileReader that decodes from r.
+// An empty format enables content sniffing.
+func NewFileReaderFrom(r io.Reader, format Format) *FileReader {
+	return &FileReader{r: r, format: format}
+}
+
+// ReadJob detects the format if necessary and decodes the job.
+func (f *FileReader) ReadJob() (*JobInput, error) {
+	data, err := readSource(f.r, f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := f.format
+	if format == "" {
+		format = SniffFormat(data)
+	}
+	decode, ok := decoders[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported job file format '%s'", format)
+	}
+	return decodeWithPath(data, f.path, decode)
+}
+
+// readJobWith loads the source and decodes it with decode.
+func readJobWith(r io.Reader, path string, decode decodeFunc) (*JobInput, error) {
+	data, err := readSource(r, path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWithPath(data, path, decode)
+}
+
+// decodeWithPath runs decode and prefixes errors with the file path, if any.
+func decodeWithPath(data []byte, path string, decode decodeFunc) (*JobInput, error) {
+	in, err := decode(data)
+	if err != nil {
+		if path != "" {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return nil, err
+	}
+	return in, nil
+}
+
+// readSource returns the full contents of r, or of the file at path when r is nil.
+func readSource(r io.Reader, path string) ([]byte, error) {
+	if r == nil {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read job file: %w", err)
+		}
+		return data, nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read error: %w", err)
+	}
+	return data, nil
+}
+
+// fieldNames returns the set of names declared by the given struct tag
+// (e.g. "yaml") on the fields of v.
+func fieldNames(v any, tag string) map[string]bool {
+	t := reflect.TypeOf(v)
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,148 @@
+package input
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Format
+	}{
+		{name: "json", src: `{"name": "J", "tasks": []}`, want: FormatJSON},
+		{name: "truncated json", src: `{"name": "J", "tasks": [`, want: FormatJSON},
+		{name: "yaml", src: "name: J\ntasks:\n  - id: A\n", want: FormatYAML},
+		{name: "yaml flow mapping", src: `{name: J, tasks: [{id: A, duration: 1}]}`, want: FormatYAML},
+		{name: "yaml after comment", src: "# a job\nname: J\n", want: FormatYAML},
+		{name: "toml table", src: "[[tasks]]\nid = \"A\"\n", want: FormatTOML},
+		{name: "toml key", src: "name = \"J\"\n", want: FormatTOML},
+		{name: "dot", src: "// comment\ndigraph J {\n}", want: FormatDOT},
+		{name: "strict dot", src: "strict digraph{}", want: FormatDOT},
+		{name: "mermaid", src: "---\ntitle: J\n---\n%% workers: 2\nflowchart LR\n", want: FormatMermaid},
+		{name: "empty", src: "", want: FormatYAML},
+	}
+	for _, tt := range tests {
+		if got := SniffFormat([]byte(tt.src)); got != tt.want {
+			t.Errorf("%s: SniffFormat = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// The examples describe the same job in every format.
+func TestReadersAgree(t *testing.T) {
+	want := readExample(t, "job.json")
+	for _, name := range []string{"job.yaml", "job.toml"} {
+		t.Run(name, func(t *testing.T) {
+			got := readExample(t, name)
+			compareJobs(t, got, want)
+			if got.Workers != want.Workers {
+				t.Errorf("workers %d, want %d", got.Workers, want.Workers)
+			}
+		})
+	}
+}
+
+// Read without a known extension, each example is recognised by its
+// contents.
+func TestReadersSniff(t *testing.T) {
+	want := readExample(t, "job.json")
+	for _, name := range []string{"job.json", "job.yaml", "job.toml", "job.dot", "job.mmd"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile("../examples/" + name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := NewFileReaderFrom(strings.NewReader(string(data)), "").ReadJob()
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareJobs(t, got, want)
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		src    string
+		want   string
+	}{
+		{name: "yaml unknown field", format: FormatYAML, src: "tasks:\n  - id: A\n    duration: 1\n    duraton: 2\n", want: `tasks[0]: line 4: unknown field "duraton"`},
+		{name: "yaml unknown command field", format: FormatYAML, src: "tasks:\n  - id: A\n    duration: 1\n    command: {args: [\"true\"], retires: 3}\n", want: `line 4: unknown field "retires"`},
+		{name: "yaml unknown top-level field", format: FormatYAML, src: "task: []\n", want: `unknown field "task"`},
+		{name: "yaml bad duration", format: FormatYAML, src: "tasks:\n  - id: A\n    duration: 0\n", want: "tasks[0].duration"},
+		{name: "yaml not a mapping", format: FormatYAML, src: "tasks:\n  - A\n", want: "tasks[0]: line 2: task must be a mapping"},
+		{name: "yaml empty", format: FormatYAML, src: "", want: "empty YAML document"},
+		{name: "toml unknown field", format: FormatTOML, src: "[[tasks]]\nid = \"A\"\nduration = 1\nduraton = 2\n", want: `tasks[0]: unknown field "duraton"`},
+		{name: "toml unknown command field", format: FormatTOML, src: "[[tasks]]\nid = \"A\"\nduration = 1\ncommand = {args = [\"true\"], retires = 3}\n", want: `unknown field "tasks.command.retires"`},
+		{name: "toml unknown top-level field", format: FormatTOML, src: "task = 1\n", want: `unknown field "task"`},
+		{name: "toml bad duration", format: FormatTOML, src: "[[tasks]]\nid = \"A\"\nduration = 0\n", want: "tasks[0].duration"},
+		{name: "toml syntax", format: FormatTOML, src: "name = \"J\"\n[[tasks]\n", want: "invalid TOML at line"},
+		{name: "unknown format", format: "xml", src: "<job/>", want: "unsupported job file format 'xml'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileReaderFrom(strings.NewReader(tt.src), tt.format).ReadJob()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func readExample(t *testing.T, name string) *JobInput {
+	t.Helper()
+	f, err := os.Open("../examples/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	format, ok := FormatFromPath(name)
+	if !ok {
+		t.Fatalf("no format for %s", name)
+	}
+	in, err := NewFileReaderFrom(f, format).ReadJob()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return in
+}
+
+// compareJobs fails unless got has the name, tasks, durations, dependencies
+// and links of want.
+func compareJobs(t *testing.T, got, want *JobInput) {
+	t.Helper()
+	if got.Job.Name != want.Job.Name {
+		t.Errorf("name %q, want %q", got.Job.Name, want.Job.Name)
+	}
+	if got.Job.TaskCount() != want.Job.TaskCount() {
+		t.Fatalf("%d task(s), want %d", got.Job.TaskCount(), want.Job.TaskCount())
+	}
+	for id, w := range want.Job.Tasks {
+		g, ok := got.Job.Tasks[id]
+		if !ok {
+			t.Errorf("task %s is missing", id)
+			continue
+		}
+		if g.Duration != w.Duration {
+			t.Errorf("task %s: duration %d, want %d", id, g.Duration, w.Duration)
+		}
+		gotDeps := append([]string(nil), g.Dependencies...)
+		wantDeps := append([]string(nil), w.Dependencies...)
+		sort.Strings(gotDeps)
+		sort.Strings(wantDeps)
+		if strings.Join(gotDeps, ",") != strings.Join(wantDeps, ",") {
+			t.Errorf("task %s: dependencies %v, want %v", id, gotDeps, wantDeps)
+		}
+		for _, dep := range wantDeps {
+			if g.Link(dep) != w.Link(dep) && !(g.Link(dep).IsPlain() && w.Link(dep).IsPlain()) {
+				t.Errorf("task %s: link from %s is %v, want %v", id, dep, g.Link(dep), w.Link(dep))
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package input
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"wingie_case/model"
+)
+
+// graphSource collects the nodes and edges of a DOT or Mermaid document
+// before they are turned into a jobDefinition. Tasks keep the order in which
+// their nodes are first mentioned; an edge "A -> B" makes B depend on A. An
+// edge label written in link notation ("SS+2", see model.ParseLink) gives
+// the link type and lag.
+type graphSource struct {
+	name    string
+	workers *int
+	order   []string
+	nodes   map[string]*graphSourceNode
+}
+
+type graphSourceNode struct {
+	id       string
+	duration int // 0 until known
+	line     int // first mention, for error messages
+	deps     []dependencyDefinition
+}
+
+func newGraphSource() *graphSource {
+	return &graphSource{nodes: make(map[string]*graphSourceNode)}
+}
+
+// node returns the node with the given ID, creating it on first mention.
+func (g *graphSource) node(id string, line int) *graphSourceNode {
+	if n, ok := g.nodes[id]; ok {
+		return n
+	}
+	n := &graphSourceNode{id: id, line: line}
+	g.nodes[id] = n
+	g.order = append(g.order, id)
+	return n
+}
+
+// edge records that to depends on from through link. Repeated edges are
+// ignored, as graph tools allow drawing the same link twice.
+func (g *graphSource) edge(from, to string, link model.Link, line int) {
+	g.node(from, line)
+	n := g.node(to, line)
+	for _, dep := range n.deps {
+		if dep.ID == from {
+			return
+		}
+	}
+	n.deps = append(n.deps, dependencyDefinition{ID: from, Type: string(link.Type), Lag: link.Lag})
+}
+
+// linkLike matches labels that are meant as link notation: a link type
+// followed by anything but letters, or a two-letter code starting like a
+// type followed by a lag ("SX+2").
+var linkLike = regexp.MustCompile(`(?i)^\s*((FS|SS|FF|SF)\b|[FS][A-Z]\s*[+-]\s*\d)`)
+
+// edgeLink returns the link an edge label describes, or a plain link when
+// the label is empty or ordinary text. A label that looks like link
+// notation but does not parse is an error rather than a plain edge.
+func edgeLink(label string, line int) (model.Link, error) {
+	link, err := model.ParseLink(label)
+	if err == nil {
+		return link, nil
+	}
+	if linkLike.MatchString(label) {
+		return model.Link{}, fmt.Errorf("line %d: %v", line, err)
+	}
+	return model.Link{}, nil
+}
+
+// setWorkers parses the worker count given as a graph attribute or comment.
+func (g *graphSource) setWorkers(value string, line int) error {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("line %d: workers: '%s' is not an integer", line, value)
+	}
+	g.workers = &n
+	return nil
+}
+
+// definition converts the collected graph. Every node must have a duration.
+func (g *graphSource) definition() (*jobDefinition, error) {
+	def := &jobDefinition{
+		Name:    g.name,
+		Workers: g.workers,
+		Tasks:   make([]taskDefinition, 0, len(g.order)),
+	}
+	for _, id := range g.order {
+		n := g.nodes[id]
+		if n.duration == 0 {
+			return nil, fmt.Errorf("line %d: node '%s' has no duration", n.line, id)
+		}
+		def.Tasks = append(def.Tasks, taskDefinition{
+			ID:           id,
+			Duration:     n.duration,
+			Dependencies: n.deps,
+		})
+	}
+	return def, nil
+}
+
+// labelDuration matches a duration written in a node label, e.g. "Build (3)".
+var labelDuration = regexp.MustCompile(`\(\s*(\d+)\s*\)`)
+
+// durationFromLabel returns the first "(N)" in label and the label text
+// before it.
+func durationFromLabel(label string) (duration int, name string, ok bool) {
+	loc := labelDuration.FindStringSubmatchIndex(label)
+	if loc == nil {
+		return 0, "", false
+	}
+	duration, err := strconv.Atoi(label[loc[2]:loc[3]])
+	if err != nil {
+		return 0, "", false
+	}
+	return duration, strings.TrimSpace(label[:loc[0]]), true
+}
+
+// parseDuration parses an explicit duration attribute; it must be positive.
+func parseDuration(id, value string, line int) (int, error) {
+	d, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("line %d: node '%s': duration must be a positive integer, got '%s'", line, id, value)
+	}
+	return d, nil
+}
@@ -0,0 +1,172 @@
+package input
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+	"wingie_case/output"
+	"wingie_case/scheduler"
+)
+
+func TestEdgeLink(t *testing.T) {
+	tests := []struct {
+		label   string
+		want    model.Link
+		wantErr bool
+	}{
+		{label: "", want: model.Link{}},
+		{label: "SS+2", want: model.Link{Type: model.StartToStart, Lag: 2}},
+		{label: "ff-1", want: model.Link{Type: model.FinishToFinish, Lag: -1}},
+		{label: "SF", want: model.Link{Type: model.StartToFinish}},
+		{label: "build step", want: model.Link{}},
+		{label: "FFmpeg", want: model.Link{}},
+		{label: "OK-1", want: model.Link{}},
+		{label: "SS+1.5", wantErr: true},
+		{label: "SX+2", wantErr: true},
+		{label: "FF +", wantErr: true},
+		{label: "FS then", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := edgeLink(tt.label, 7)
+		if tt.wantErr {
+			if err == nil || !strings.HasPrefix(err.Error(), "line 7: ") {
+				t.Errorf("edgeLink(%q) error = %v, want one for line 7", tt.label, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("edgeLink(%q) = %v, %v, want %v", tt.label, got, err, tt.want)
+		}
+	}
+}
+
+func TestGraphReaders(t *testing.T) {
+	want := readExample(t, "job.json")
+	for _, name := range []string{"job.dot", "job.mmd"} {
+		t.Run(name, func(t *testing.T) {
+			got := readExample(t, name)
+			compareJobs(t, got, want)
+		})
+	}
+}
+
+func TestGraphReaderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		src    string
+		want   string
+	}{
+		{
+			name:   "dot undirected edge",
+			format: FormatDOT,
+			src:    "digraph {\n  a [duration=1];\n  a -- b;\n}",
+			want:   "line 3: undirected edge",
+		},
+		{
+			name:   "dot missing duration",
+			format: FormatDOT,
+			src:    "digraph {\n  a [duration=1];\n\n  a -> b;\n}",
+			want:   "line 4: node 'b' has no duration",
+		},
+		{
+			name:   "dot bad duration",
+			format: FormatDOT,
+			src:    "digraph {\n  a [duration=x];\n}",
+			want:   "line 2: node 'a': duration must be a positive integer",
+		},
+		{
+			name:   "dot bad workers",
+			format: FormatDOT,
+			src:    "digraph {\n  workers=two;\n  a [duration=1];\n}",
+			want:   "line 2: workers",
+		},
+		{
+			name:   "dot malformed link",
+			format: FormatDOT,
+			src:    "digraph {\n  a [duration=1]; b [duration=2];\n  a -> b [label=\"SS+1.5\"];\n}",
+			want:   "line 3: invalid link 'SS+1.5'",
+		},
+		{
+			name:   "mermaid missing header",
+			format: FormatMermaid,
+			src:    "%% workers: 2\nA[A (1)] --> B[B (2)]",
+			want:   "line 2: expected 'flowchart' or 'graph' declaration",
+		},
+		{
+			name:   "mermaid undirected link",
+			format: FormatMermaid,
+			src:    "flowchart LR\n  A[A (1)] --> B[B (2)]\n  B --- C[C (1)]",
+			want:   "line 3: undirected link",
+		},
+		{
+			name:   "mermaid missing duration",
+			format: FormatMermaid,
+			src:    "flowchart LR\n\n  A[A (1)] --> B",
+			want:   "line 3: node 'B' has no duration",
+		},
+		{
+			name:   "mermaid malformed link",
+			format: FormatMermaid,
+			src:    "flowchart LR\n  A[A (1)] -->|FF +| B[B (2)]",
+			want:   "line 2: invalid link 'FF +'",
+		},
+		{
+			name:   "mermaid bad workers",
+			format: FormatMermaid,
+			src:    "%% workers: many\nflowchart LR\n  A[A (1)]",
+			want:   "line 1: workers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileReaderFrom(strings.NewReader(tt.src), tt.format).ReadJob()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Exporting a job with the graph printers and reading it back must give
+// the same job, typed links included.
+func TestGraphRoundTrip(t *testing.T) {
+	in := readExample(t, "job.json")
+	job := in.Job
+	job.Tasks["D"].Links = map[string]model.Link{"A": {Type: model.StartToStart, Lag: 1}}
+	job.Tasks["E"].Links = map[string]model.Link{"C": {Type: model.FinishToFinish, Lag: -1}}
+	job.Tasks["F"].Links = map[string]model.Link{"E": {Type: model.StartToFinish, Lag: 2}}
+	result, err := scheduler.NewWorkerScheduler().Schedule(job, in.Workers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format  Format
+		printer func(w *bytes.Buffer) output.GraphPrinter
+	}{
+		{FormatDOT, func(w *bytes.Buffer) output.GraphPrinter { return output.NewDOTPrinterWithWriter(w) }},
+		{FormatMermaid, func(w *bytes.Buffer) output.GraphPrinter { return output.NewMermaidPrinterWithWriter(w) }},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			for _, res := range []*model.ScheduleResult{nil, result} {
+				var buf bytes.Buffer
+				tt.printer(&buf).PrintGraph(job, res)
+				got, err := NewFileReaderFrom(&buf, tt.format).ReadJob()
+				if err != nil {
+					t.Fatalf("reading back %s: %v", buf.String(), err)
+				}
+				compareJobs(t, got, in)
+				if res == nil {
+					continue
+				}
+				if got.Workers != in.Workers {
+					t.Errorf("workers %d, want %d", got.Workers, in.Workers)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JSONReader reads a job definition from a JSON document:
+//
+//	{
+//	  "name": "J",
+//	  "workers": 2,
+//	  "tasks": [
+//	    {"id": "A", "duration": 3},
+//	    {"id": "D", "duration": 5, "dependencies": ["A"]}
+//	  ]
+//	}
+//
+// Unknown fields are rejected so that typos do not go unnoticed.
+type JSONReader struct {
+	r    io.Reader
+	path string
+}
+
+// NewJSONReader creates a JSONReader that decodes from r.
+func NewJSONReader(r io.Reader) *JSONReader {
+	return &JSONReader{r: r}
+}
+
+// NewJSONFileReader creates a JSONReader that decodes the file at path.
+// The file is opened when ReadJob is called.
+func NewJSONFileReader(path string) *JSONReader {
+	return &JSONReader{path: path}
+}
+
+// jsonJob is a jobDefinition whose tasks are still raw.
+type jsonJob struct {
+	jobDefinition
+	Tasks []json.RawMessage `json:"tasks"`
+}
+
+// ReadJob decodes the document and converts it into a JobInput.
+func (j *JSONReader) ReadJob() (*JobInput, error) {
+	return readJobWith(j.r, j.path, decodeJSONJob)
+}
+
+// decodeJSONJob decodes a JSON job document. Each task is decoded on its own
+// so that errors can point at the offending array index.
+func decodeJSONJob(data []byte) (*JobInput, error) {
+	var doc jsonJob
+	if err := decodeStrict(data, &doc); err != nil {
+		return nil, describeJSONError(data, "", err)
+	}
+
+	def := doc.jobDefinition
+	def.Tasks = make([]taskDefinition, 0, len(doc.Tasks))
+	for i, raw := range doc.Tasks {
+		prefix := fmt.Sprintf("tasks[%d]", i)
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			return nil, fmt.Errorf("%s: task must be an object, got null", prefix)
+		}
+		var t taskDefinition
+		if err := decodeStrict(raw, &t); err != nil {
+			return nil, describeJSONError(raw, prefix, err)
+		}
+		def.Tasks = append(def.Tasks, t)
+	}
+
+	return def.build()
+}
+
+// decodeStrict unmarshals a single JSON value, rejecting unknown fields
+// and trailing data.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after the top-level value")
+	}
+	return nil
+}
+
+// describeJSONError rewrites encoding/json errors into messages that name
+// the offending field (prefixed with prefix) or the line and column.
+func describeJSONError(data []byte, prefix string, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := lineColumn(data, syntaxErr.Offset)
+		return fmt.Errorf("invalid JSON at line %d, column %d: %s", line, col, syntaxErr.Error())
+	case errors.As(err, &typeErr):
+		field := joinField(prefix, typeErr.Field)
+		return fmt.Errorf("%s: expected %s, got JSON %s", field, typeErr.Type, typeErr.Value)
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("empty JSON document")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("unexpected end of JSON input")
+	}
+
+	msg := strings.TrimPrefix(err.Error(), "json: ")
+	if prefix != "" {
+		return fmt.Errorf("%s: %s", prefix, msg)
+	}
+	return errors.New(msg)
+}
+
+func joinField(prefix, field string) string {
+	switch {
+	case prefix == "":
+		return field
+	case field == "":
+		return prefix
+	}
+	return prefix + "." + field
+}
+
+// lineColumn converts a byte offset into a 1-based line and column.
+func lineColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, col := 1, 1
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
@@ -0,0 +1,95 @@
+package input
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+func TestJSONReader(t *testing.T) {
+	f, err := os.Open("../examples/job.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	in, err := NewJSONReader(f).ReadJob()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Job.Name != "J" || in.Workers != 2 || in.Job.TaskCount() != 6 {
+		t.Errorf("job %q with %d task(s) on %d worker(s), want J with 6 on 2",
+			in.Job.Name, in.Job.TaskCount(), in.Workers)
+	}
+	task, ok := in.Job.Tasks["F"]
+	if !ok || task.Duration != 3 || strings.Join(task.Dependencies, ",") != "D,E" {
+		t.Errorf("task F = %+v, want duration 3 after D and E", task)
+	}
+}
+
+func TestJSONReaderDefaults(t *testing.T) {
+	in, err := NewJSONReader(strings.NewReader(`{"tasks": [{"id": "A", "duration": 1}, {"id": "B", "duration": 2}]}`)).ReadJob()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Job.Name != "Job" || in.Workers != 2 {
+		t.Errorf("job %q on %d worker(s), want the default name and one worker per task", in.Job.Name, in.Workers)
+	}
+}
+
+func TestJSONReaderPool(t *testing.T) {
+	in, err := NewJSONReader(strings.NewReader(`{"pool": [{"name": "slow"}, {"speed": 2.5, "tags": [" gpu "]}], "tasks": [{"id": "A", "duration": 1, "requires": ["gpu"]}]}`)).ReadJob()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.Worker{{ID: 1, Name: "slow", Speed: 1}, {ID: 2, Name: "W2", Speed: 2.5, Tags: []string{"gpu"}}}
+	if in.Workers != 2 || !reflect.DeepEqual(in.Job.Pool, want) {
+		t.Errorf("pool %+v on %d worker(s), want %+v", in.Job.Pool, in.Workers, want)
+	}
+	if got := in.Job.Tasks["A"].Requires; len(got) != 1 || got[0] != "gpu" {
+		t.Errorf("A requires %v, want gpu", got)
+	}
+}
+
+func TestJSONReaderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "unknown field", src: `{"tasks": [{"id": "A", "duration": 1, "duraton": 2}]}`, want: "duraton"},
+		{name: "unknown top-level field", src: `{"task": []}`, want: "task"},
+		{name: "unknown command field", src: `{"tasks": [{"id": "A", "duration": 1, "command": {"args": ["true"], "retires": 3}}]}`, want: "retires"},
+		{name: "no tasks", src: `{"tasks": []}`, want: "tasks: at least one task is required"},
+		{name: "bad duration", src: `{"tasks": [{"id": "A", "duration": 0}]}`, want: "tasks[0].duration"},
+		{name: "empty id", src: `{"tasks": [{"id": " ", "duration": 1}]}`, want: "tasks[0].id"},
+		{name: "duplicate id", src: `{"tasks": [{"id": "A", "duration": 1}, {"id": "A", "duration": 1}]}`, want: "tasks[1].id: duplicate task ID 'A'"},
+		{name: "duplicate dependency", src: `{"tasks": [{"id": "A", "duration": 1}, {"id": "B", "duration": 1, "dependencies": ["A", "A"]}]}`, want: "tasks[1].dependencies[1]"},
+		{name: "bad workers", src: `{"workers": 0, "tasks": [{"id": "A", "duration": 1}]}`, want: "workers"},
+		{name: "null task", src: `{"tasks": [null]}`, want: "tasks[0]: task must be an object"},
+		{name: "syntax", src: "{\n  \"tasks\": [,]\n}", want: "line 2"},
+		{name: "truncated", src: `{"tasks": [`, want: "unexpected end"},
+		{name: "command without program", src: `{"tasks": [{"id": "A", "duration": 1, "command": {"args": [" "]}}]}`, want: "tasks[0].command.args: a program name is required"},
+		{name: "command bad env", src: `{"tasks": [{"id": "A", "duration": 1, "command": {"args": ["true"], "env": {"A=B": "x"}}}]}`, want: "tasks[0].command.env: invalid variable name 'A=B'"},
+		{name: "pool speed", src: `{"pool": [{"speed": 0}], "tasks": [{"id": "A", "duration": 1}]}`, want: "pool[0].speed: speed must be a positive number, got 0"},
+		{name: "pool duplicate name", src: `{"pool": [{"name": "x"}, {"name": "x"}], "tasks": [{"id": "A", "duration": 1}]}`, want: "pool[1].name: duplicate worker name 'x'"},
+		{name: "pool and workers", src: `{"workers": 3, "pool": [{}], "tasks": [{"id": "A", "duration": 1}]}`, want: "workers: worker count 3 does not match the pool of 1 worker(s)"},
+		{name: "empty tag", src: `{"pool": [{"tags": ["gpu", " "]}], "tasks": [{"id": "A", "duration": 1}]}`, want: "pool[0].tags[1]: tag cannot be empty"},
+		{name: "duplicate requirement", src: `{"tasks": [{"id": "A", "duration": 1, "requires": ["gpu", "gpu"]}]}`, want: "tasks[0].requires[1]: duplicate tag 'gpu'"},
+		{name: "zero capacity", src: `{"resources": {"cpu": 0}, "tasks": [{"id": "A", "duration": 1}]}`, want: "resources.cpu: capacity must be positive, got 0"},
+		{name: "duplicate resource", src: `{"resources": {"cpu": 1}, "tasks": [{"id": "A", "duration": 1, "resources": {"cpu": 1, " cpu": 1}}]}`, want: "tasks[0].resources.cpu: duplicate resource name"},
+		{name: "negative retries", src: `{"tasks": [{"id": "A", "duration": 1, "command": {"args": ["true"], "retries": -1}}]}`, want: "tasks[0].command.retries"},
+		{name: "too many retries", src: `{"tasks": [{"id": "A", "duration": 1, "command": {"args": ["true"], "retries": 1000000}}]}`, want: "retry count must be between 0 and 100, got 1000000"},
+		{name: "bad timeout", src: `{"tasks": [{"id": "A", "duration": 1, "command": {"args": ["true"], "timeout": "soon"}}]}`, want: "tasks[0].command.timeout: invalid duration 'soon'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJSONReader(strings.NewReader(tt.src)).ReadJob()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
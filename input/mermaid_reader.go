@@ -0,0 +1,371 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"wingie_case/model"
+)
+
+// MermaidReader reads a job from a Mermaid flowchart:
+//
+//	%% workers: 2
+//	flowchart LR
+//	  A[A (3)] --> D[D (5)] --> F[F (3)]
+//	  B[B (2)] & C[C (4)] --> E[E (2)] --> F
+//
+// Each node needs a label containing its duration as "(N)". The label text
+// before the duration is the task ID, so "t0[\"Build (3)\"]" is task
+// "Build"; without text before it the node ID is used. An edge A --> D makes
+// D depend on A; "==>" and "-.->" links and "|text|" labels are accepted,
+// and "&" joins several nodes on either side. A label in link notation, as
+// in A -->|SS+2| D, types the link and sets its lag (see model.ParseLink). The job name comes from the
+// front matter title or a "%% name: J" comment, the worker count from a
+// "%% workers: N" comment. Styling lines (classDef, class, style,
+// linkStyle, click) and subgraph boundaries are ignored, so the output of
+// MermaidPrinter reads back unchanged.
+type MermaidReader struct {
+	r    io.Reader
+	path string
+}
+
+// NewMermaidReader creates a MermaidReader that decodes from r.
+func NewMermaidReader(r io.Reader) *MermaidReader {
+	return &MermaidReader{r: r}
+}
+
+// NewMermaidFileReader creates a MermaidReader that decodes the file at path.
+// The file is opened when ReadJob is called.
+func NewMermaidFileReader(path string) *MermaidReader {
+	return &MermaidReader{path: path}
+}
+
+// ReadJob parses the flowchart and converts it into a JobInput.
+func (m *MermaidReader) ReadJob() (*JobInput, error) {
+	return readJobWith(m.r, m.path, decodeMermaidJob)
+}
+
+var (
+	// mermaidHeader matches the diagram declaration, e.g. "flowchart LR".
+	mermaidHeader = regexp.MustCompile(`^(flowchart|graph)(\s+(TB|TD|BT|RL|LR))?\s*;?$`)
+	// mermaidComment matches "%% key: value" metadata comments.
+	mermaidComment = regexp.MustCompile(`^%%\s*(\w+)\s*:\s*(.*?)\s*$`)
+	// mermaidLink matches a directed link with an optional |text| label:
+	// -->, ==>, -.->, and the "-- text -->" form.
+	mermaidLink = regexp.MustCompile(`^\s*(-->|==>|-\.->|--[^->|][^>]*?-->|==[^=>|][^>]*?==>)\s*(\|[^|]*\|)?\s*`)
+	// mermaidNodeID matches a node identifier.
+	mermaidNodeID = regexp.MustCompile(`^[\p{L}\p{N}_][\p{L}\p{N}_.]*`)
+)
+
+// mermaidIgnored lists statements that do not affect the job.
+var mermaidIgnored = []string{"classDef ", "class ", "style ", "linkStyle ", "click ", "subgraph ", "direction "}
+
+func decodeMermaidJob(data []byte) (*JobInput, error) {
+	lines, err := splitMermaid(data)
+	if err != nil {
+		return nil, err
+	}
+	m := &mermaidParser{graph: newGraphSource(), labels: map[string]string{}}
+
+	i := 0
+	// Front matter: "---", "title: J", "---".
+	if i < len(lines) && lines[i].text == "---" {
+		for i++; i < len(lines) && lines[i].text != "---"; i++ {
+			if key, value, ok := strings.Cut(lines[i].text, ":"); ok && strings.TrimSpace(key) == "title" {
+				m.graph.name = unquoteTitle(strings.TrimSpace(value))
+			}
+		}
+		if i == len(lines) {
+			return nil, fmt.Errorf("unterminated front matter")
+		}
+		i++
+	}
+
+	header := false
+	for ; i < len(lines); i++ {
+		l := lines[i]
+		if strings.HasPrefix(l.text, "%%") {
+			if err := m.comment(l); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !header {
+			if !mermaidHeader.MatchString(l.text) {
+				return nil, fmt.Errorf("line %d: expected 'flowchart' or 'graph' declaration, got '%s'", l.number, l.text)
+			}
+			header = true
+			continue
+		}
+		if err := m.statement(l); err != nil {
+			return nil, err
+		}
+	}
+	if !header {
+		return nil, fmt.Errorf("empty Mermaid document")
+	}
+
+	if err := m.resolve(); err != nil {
+		return nil, err
+	}
+	def, err := m.graph.definition()
+	if err != nil {
+		return nil, err
+	}
+	return def.build()
+}
+
+type mermaidLine struct {
+	text   string
+	number int
+}
+
+// splitMermaid returns the non-empty, trimmed lines; statements separated
+// by ';' on one line are split apart.
+func splitMermaid(data []byte) ([]mermaidLine, error) {
+	var lines []mermaidLine
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "%%") || text == "---" {
+			lines = append(lines, mermaidLine{text: text, number: n})
+			continue
+		}
+		for _, part := range splitOutsideQuotes(text, ';') {
+			if part = strings.TrimSpace(part); part != "" {
+				lines = append(lines, mermaidLine{text: part, number: n})
+			}
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitOutsideQuotes splits s at sep, except inside double quotes.
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func unquoteTitle(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return strings.ReplaceAll(s[1:len(s)-1], `\"`, `"`)
+	}
+	return s
+}
+
+// mermaidParser collects nodes under their Mermaid IDs; resolve renames
+// them to task IDs taken from the labels once all lines are read.
+type mermaidParser struct {
+	graph  *graphSource
+	labels map[string]string // node ID -> label
+}
+
+func (m *mermaidParser) comment(l mermaidLine) error {
+	match := mermaidComment.FindStringSubmatch(l.text)
+	if match == nil {
+		return nil
+	}
+	switch strings.ToLower(match[1]) {
+	case "workers":
+		return m.graph.setWorkers(match[2], l.number)
+	case "name":
+		m.graph.name = match[2]
+	}
+	return nil
+}
+
+// statement parses "group link group link group ...", where a group is one
+// or more nodes joined by '&'.
+func (m *mermaidParser) statement(l mermaidLine) error {
+	if l.text == "end" {
+		return nil
+	}
+	for _, prefix := range mermaidIgnored {
+		if strings.HasPrefix(l.text, prefix) {
+			return nil
+		}
+	}
+
+	rest := l.text
+	var previous []string
+	var link model.Link // of the arrow before the current group
+	var err error
+	for {
+		var group []string
+		for {
+			id, remaining, err := m.node(rest, l.number)
+			if err != nil {
+				return err
+			}
+			group = append(group, id)
+			rest = strings.TrimSpace(remaining)
+			if !strings.HasPrefix(rest, "&") {
+				break
+			}
+			rest = strings.TrimSpace(rest[1:])
+		}
+		for _, from := range previous {
+			for _, to := range group {
+				m.graph.edge(from, to, link, l.number)
+			}
+		}
+		if rest == "" {
+			return nil
+		}
+
+		arrow := mermaidLink.FindStringSubmatch(rest)
+		if arrow == nil {
+			if strings.HasPrefix(rest, "---") || strings.HasPrefix(rest, "-.-") || strings.HasPrefix(rest, "===") {
+				return fmt.Errorf("line %d: undirected link, use '-->'", l.number)
+			}
+			return fmt.Errorf("line %d: expected a link such as '-->', got '%s'", l.number, rest)
+		}
+		rest = rest[len(arrow[0]):]
+		link, err = edgeLink(arrowLabel(arrow), l.number)
+		if err != nil {
+			return err
+		}
+		previous = group
+	}
+}
+
+// arrowLabel returns the text of a link matched by mermaidLink, given as
+// "|text|" or inside the arrow as in "-- text -->".
+func arrowLabel(arrow []string) string {
+	if arrow[2] != "" {
+		return strings.Trim(arrow[2], "|")
+	}
+	if a := arrow[1]; len(a) > 4 && (strings.HasPrefix(a, "--") || strings.HasPrefix(a, "==")) {
+		return a[2 : len(a)-3]
+	}
+	return ""
+}
+
+// mermaidShapes maps opening bracket sequences to their closing sequences,
+// longest first so that "([" wins over "(".
+var mermaidShapes = [][2]string{
+	{"(((", ")))"}, {"([", "])"}, {"[[", "]]"}, {"[(", ")]"}, {"((", "))"}, {"{{", "}}"},
+	{"[/", "/]"}, {"[\\", "\\]"}, {"[/", "\\]"}, {"[\\", "/]"},
+	{"[", "]"}, {"(", ")"}, {"{", "}"}, {">", "]"},
+}
+
+// node parses a node ID with an optional shape and label, declares it and
+// returns the rest of the line.
+func (m *mermaidParser) node(s string, line int) (string, string, error) {
+	id := mermaidNodeID.FindString(s)
+	if id == "" {
+		return "", "", fmt.Errorf("line %d: expected a node ID, got '%s'", line, s)
+	}
+	m.graph.node(id, line)
+	rest := s[len(id):]
+
+	for _, shape := range mermaidShapes {
+		if !strings.HasPrefix(rest, shape[0]) {
+			continue
+		}
+		body := rest[len(shape[0]):]
+		var label string
+		if strings.HasPrefix(body, `"`) {
+			end := strings.Index(body[1:], `"`)
+			if end < 0 {
+				return "", "", fmt.Errorf("line %d: unterminated label of node '%s'", line, id)
+			}
+			label = body[1 : end+1]
+			body = body[end+2:]
+		} else {
+			end := closingIndex(body, shape[1])
+			if end < 0 {
+				return "", "", fmt.Errorf("line %d: unterminated shape of node '%s'", line, id)
+			}
+			label = body[:end]
+			body = body[end:]
+		}
+		if !strings.HasPrefix(body, shape[1]) {
+			continue // e.g. "[/" opening closed by "\]": try the next shape
+		}
+		m.labels[id] = label
+		return id, body[len(shape[1]):], nil
+	}
+	return id, rest, nil
+}
+
+// closingIndex returns the index of the first close sequence in s that is
+// not inside brackets opened within s, so "Build (2))" closes at the last
+// ')'. It returns -1 when there is none.
+func closingIndex(s, close string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		if depth == 0 && strings.HasPrefix(s[i:], close) {
+			return i
+		}
+		switch s[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+	}
+	return -1
+}
+
+// resolve sets durations from the labels and renames nodes whose label
+// names the task.
+func (m *mermaidParser) resolve() error {
+	g := m.graph
+	rename := make(map[string]string, len(g.order))
+	for _, id := range g.order {
+		label := strings.ReplaceAll(m.labels[id], "#quot;", `"`)
+		first, _, _ := strings.Cut(strings.ReplaceAll(label, "<br>", "<br/>"), "<br/>")
+		d, name, ok := durationFromLabel(first)
+		if !ok {
+			return fmt.Errorf("line %d: node '%s' has no duration; write it in the label, e.g. %s[\"%s (3)\"]",
+				g.nodes[id].line, id, id, id)
+		}
+		if d <= 0 {
+			return fmt.Errorf("line %d: node '%s': duration must be positive, got %d", g.nodes[id].line, id, d)
+		}
+		g.nodes[id].duration = d
+		if name != "" {
+			rename[id] = name
+		}
+	}
+
+	nodes := make(map[string]*graphSourceNode, len(g.order))
+	order := make([]string, 0, len(g.order))
+	for _, id := range g.order {
+		n := g.nodes[id]
+		if name, ok := rename[id]; ok {
+			n.id = name
+		}
+		for j, dep := range n.deps {
+			if name, ok := rename[dep.ID]; ok {
+				n.deps[j].ID = name
+			}
+		}
+		if _, exists := nodes[n.id]; exists {
+			return fmt.Errorf("line %d: duplicate task ID '%s'", n.line, n.id)
+		}
+		nodes[n.id] = n
+		order = append(order, n.id)
+	}
+	g.nodes, g.order = nodes, order
+	return nil
+}
@@ -92,36 +92,60 @@ func (c *CLIReader) readTask(index int) (*model.Task, error) {
 	}
 
 	depsStr, err := c.promptString(
-		fmt.Sprintf("Dependencies for task '%s' (comma-separated, or leave empty)", id))
+		fmt.Sprintf("Dependencies for task '%s' (comma-separated, e.g. A or A:SS+2, or leave empty)", id))
 	if err != nil {
 		return nil, err
 	}
 
-	deps := parseDependencies(depsStr, id)
+	deps, links, err := parseDependencies(depsStr, id)
+	if err != nil {
+		return nil, err
+	}
 
-	return model.NewTask(id, duration, deps)
+	task, err := model.NewTask(id, duration, deps)
+	if err != nil {
+		return nil, err
+	}
+	task.Links = links
+	return task, nil
 }
 
 // parseDependencies splits a comma-separated string into dependency IDs.
-// It filters out blanks, duplicates, and self-references.
-func parseDependencies(input string, selfID string) []string {
+// An ID may be followed by a colon and a link in the notation of
+// model.ParseLink, e.g. "A:SS+2"; the links that are not plain are returned
+// by dependency ID. It filters out blanks, duplicates, and self-references.
+func parseDependencies(input string, selfID string) ([]string, map[string]model.Link, error) {
 	if input == "" {
-		return nil
+		return nil, nil, nil
 	}
 
 	var deps []string
+	var links map[string]model.Link
 	seen := make(map[string]bool)
 
 	for _, p := range strings.Split(input, ",") {
-		dep := strings.TrimSpace(p)
+		dep, notation, typed := strings.Cut(p, ":")
+		dep = strings.TrimSpace(dep)
 		if dep == "" || dep == selfID || seen[dep] {
 			continue
 		}
+		if typed {
+			link, err := model.ParseLink(notation)
+			if err != nil {
+				return nil, nil, fmt.Errorf("dependency '%s': %v", dep, err)
+			}
+			if !link.IsPlain() {
+				if links == nil {
+					links = make(map[string]model.Link)
+				}
+				links[dep] = link
+			}
+		}
 		seen[dep] = true
 		deps = append(deps, dep)
 	}
 
-	return deps
+	return deps, links, nil
 }
 
 func (c *CLIReader) promptString(message string) (string, error) {
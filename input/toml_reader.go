@@ -0,0 +1,107 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+// TOMLReader reads a job definition from a TOML document:
+//
+//	name = "J"
+//	workers = 2
+//
+//	[[tasks]]
+//	id = "A"
+//	duration = 3
+//
+//	[[tasks]]
+//	id = "D"
+//	duration = 5
+//	dependencies = ["A"]
+//
+// The schema is the same as for JSONReader.
+type TOMLReader struct {
+	r    io.Reader
+	path string
+}
+
+// NewTOMLReader creates a TOMLReader that decodes from r.
+func NewTOMLReader(r io.Reader) *TOMLReader {
+	return &TOMLReader{r: r}
+}
+
+// NewTOMLFileReader creates a TOMLReader that decodes the file at path.
+// The file is opened when ReadJob is called.
+func NewTOMLFileReader(path string) *TOMLReader {
+	return &TOMLReader{path: path}
+}
+
+// tomlJob is a jobDefinition whose tasks are still primitives.
+type tomlJob struct {
+	jobDefinition
+	Tasks []toml.Primitive `toml:"tasks"`
+}
+
+// ReadJob decodes the document and converts it into a JobInput.
+func (t *TOMLReader) ReadJob() (*JobInput, error) {
+	return readJobWith(t.r, t.path, decodeTOMLJob)
+}
+
+// decodeTOMLJob decodes a TOML job document. Tasks are decoded lazily from
+// primitives so that errors can point at the offending array index.
+func decodeTOMLJob(data []byte) (*JobInput, error) {
+	var doc tomlJob
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("invalid TOML at line %d, column %d: %s",
+				parseErr.Position.Line, parseErr.Position.Col, parseErr.Message)
+		}
+		return nil, err
+	}
+	for _, key := range md.Undecoded() {
+		if len(key) > 0 && key[0] == "tasks" {
+			continue // checked per task below
+		}
+		return nil, fmt.Errorf("unknown field %q", key.String())
+	}
+
+	allowed := fieldNames(taskDefinition{}, "toml")
+	def := doc.jobDefinition
+	def.Tasks = make([]taskDefinition, 0, len(doc.Tasks))
+	for i, prim := range doc.Tasks {
+		prefix := fmt.Sprintf("tasks[%d]", i)
+
+		var fields map[string]any
+		if err := md.PrimitiveDecode(prim, &fields); err != nil {
+			return nil, fmt.Errorf("%s: task must be a table: %v", prefix, err)
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !allowed[key] {
+				return nil, fmt.Errorf("%s: unknown field %q", prefix, key)
+			}
+		}
+
+		var t taskDefinition
+		if err := md.PrimitiveDecode(prim, &t); err != nil {
+			return nil, fmt.Errorf("%s: %v", prefix, err)
+		}
+		def.Tasks = append(def.Tasks, t)
+	}
+	// What is left over lies in a nested table, such as a task's command.
+	for _, key := range md.Undecoded() {
+		return nil, fmt.Errorf("unknown field %q", key.String())
+	}
+
+	return def.build()
+}
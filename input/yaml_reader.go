@@ -0,0 +1,121 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLReader reads a job definition from a YAML document:
+//
+//	name: J
+//	workers: 2
+//	tasks:
+//	  - id: A
+//	    duration: 3
+//	  - id: D
+//	    duration: 5
+//	    dependencies: [A]
+//
+// The schema is the same as for JSONReader.
+type YAMLReader struct {
+	r    io.Reader
+	path string
+}
+
+// NewYAMLReader creates a YAMLReader that decodes from r.
+func NewYAMLReader(r io.Reader) *YAMLReader {
+	return &YAMLReader{r: r}
+}
+
+// NewYAMLFileReader creates a YAMLReader that decodes the file at path.
+// The file is opened when ReadJob is called.
+func NewYAMLFileReader(path string) *YAMLReader {
+	return &YAMLReader{path: path}
+}
+
+// yamlJob is a jobDefinition whose tasks are still nodes.
+type yamlJob struct {
+	jobDefinition `yaml:",inline"`
+	Tasks         []yaml.Node `yaml:"tasks"`
+}
+
+// ReadJob decodes the document and converts it into a JobInput.
+func (y *YAMLReader) ReadJob() (*JobInput, error) {
+	return readJobWith(y.r, y.path, decodeYAMLJob)
+}
+
+// decodeYAMLJob decodes a YAML job document. Tasks are kept as nodes and
+// decoded one by one so that errors can point at the offending list index.
+func decodeYAMLJob(data []byte) (*JobInput, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var doc yamlJob
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("empty YAML document")
+		}
+		return nil, describeYAMLError("", err)
+	}
+
+	allowed := fieldNames(taskDefinition{}, "yaml")
+	def := doc.jobDefinition
+	def.Tasks = make([]taskDefinition, 0, len(doc.Tasks))
+	for i := range doc.Tasks {
+		node := &doc.Tasks[i]
+		prefix := fmt.Sprintf("tasks[%d]", i)
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s: line %d: task must be a mapping", prefix, node.Line)
+		}
+		for k := 0; k+1 < len(node.Content); k += 2 {
+			key := node.Content[k]
+			if !allowed[key.Value] {
+				return nil, fmt.Errorf("%s: line %d: unknown field %q", prefix, key.Line, key.Value)
+			}
+		}
+
+		var t taskDefinition
+		if err := node.Decode(&t); err != nil {
+			return nil, describeYAMLError(prefix, err)
+		}
+		def.Tasks = append(def.Tasks, t)
+	}
+
+	// Node.Decode ignores KnownFields, so decode the whole document once more
+	// to reject unknown fields in nested mappings, such as a task's command.
+	var strict struct {
+		jobDefinition `yaml:",inline"`
+		Tasks         []taskDefinition `yaml:"tasks"`
+	}
+	dec = yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&strict); err != nil {
+		return nil, describeYAMLError("", err)
+	}
+
+	return def.build()
+}
+
+// unknownYAMLField matches the decoder's message for fields rejected by KnownFields.
+var unknownYAMLField = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// describeYAMLError strips the "yaml: " prefix and flattens multi-line
+// unmarshal errors into a single message.
+func describeYAMLError(prefix string, err error) error {
+	var typeErr *yaml.TypeError
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if errors.As(err, &typeErr) {
+		msg = strings.Join(typeErr.Errors, "; ")
+	}
+	msg = unknownYAMLField.ReplaceAllString(msg, `unknown field "$1"`)
+	if prefix != "" {
+		return fmt.Errorf("%s: %s", prefix, msg)
+	}
+	return errors.New(msg)
+}
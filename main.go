@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"wingie_case/input"
+	"wingie_case/model"
 	"wingie_case/output"
 	"wingie_case/scheduler"
 	"wingie_case/validator"
@@ -16,6 +18,11 @@ type App struct {
 	validator validator.Validator
 	scheduler scheduler.Scheduler
 	printer   output.Printer
+
+	// workers overrides the worker count read from the input when positive.
+	workers int
+	// warnings receives lint warnings when the validator is a Linter.
+	warnings io.Writer
 }
 
 // NewApp creates an App with the given dependencies.
@@ -33,46 +40,79 @@ func NewApp(
 	}
 }
 
+// WithWorkers makes the App use the given worker count instead of the one
+// supplied by the reader. Non-positive values keep the reader's count.
+func (a *App) WithWorkers(workers int) *App {
+	a.workers = workers
+	return a
+}
+
+// WithWarnings makes Check write the validator's lint warnings to w, one per
+// line. Without it warnings are not computed.
+func (a *App) WithWarnings(w io.Writer) *App {
+	a.warnings = w
+	return a
+}
+
 // Run executes the full pipeline: read → validate → schedule → print.
 func (a *App) Run() error {
-	printWelcome()
-
-	in, err := a.reader.ReadJob()
+	_, result, err := a.Schedule()
 	if err != nil {
-		return fmt.Errorf("input error: %w", err)
+		return err
 	}
 
-	if err := a.validator.Validate(in.Job); err != nil {
-		return fmt.Errorf("validation error: %w", err)
+	a.printer.Print(result)
+	return nil
+}
+
+// Schedule reads, validates and schedules the job without printing it.
+func (a *App) Schedule() (*input.JobInput, *model.ScheduleResult, error) {
+	in, err := a.Check()
+	if err != nil {
+		return nil, nil, err
 	}
 
 	result, err := a.scheduler.Schedule(in.Job, in.Workers)
 	if err != nil {
-		return fmt.Errorf("scheduling error: %w", err)
+		return nil, nil, &stageError{stage: stageScheduling, err: err}
 	}
+	return in, result, nil
+}
 
-	a.printer.Print(result)
-	return nil
+// Check reads and validates the job without scheduling it, then reports
+// lint warnings if WithWarnings was used.
+func (a *App) Check() (*input.JobInput, error) {
+	in, err := a.reader.ReadJob()
+	if err != nil {
+		return nil, &stageError{stage: stageInput, err: err}
+	}
+	if a.workers > 0 {
+		if n := len(in.Job.Pool); n > 0 && a.workers != n {
+			return nil, &stageError{stage: stageInput,
+				err: fmt.Errorf("workers: the job declares a pool of %d worker(s); edit the pool instead of overriding the count", n)}
+		}
+		in.Workers = a.workers
+	}
+
+	if err := a.validator.Validate(in.Job); err != nil {
+		return nil, &stageError{stage: stageValidation, err: err}
+	}
+	if linter, ok := a.validator.(validator.Linter); ok && a.warnings != nil {
+		for _, warning := range linter.Lint(in.Job) {
+			fmt.Fprintf(a.warnings, "warning: %s\n", warning)
+		}
+	}
+	return in, nil
 }
 
-func printWelcome() {
-	fmt.Println("╔══════════════════════════════════════════════════════════╗")
-	fmt.Println("║       Job Scheduler - Critical Path Calculator          ║")
-	fmt.Println("║       Wingie EnUygun Group - Case Study                 ║")
-	fmt.Println("╚══════════════════════════════════════════════════════════╝")
-	fmt.Println()
+func printWelcome(w io.Writer) {
+	fmt.Fprintln(w, "╔══════════════════════════════════════════════════════════╗")
+	fmt.Fprintln(w, "║       Job Scheduler - Critical Path Calculator          ║")
+	fmt.Fprintln(w, "║       Wingie EnUygun Group - Case Study                 ║")
+	fmt.Fprintln(w, "╚══════════════════════════════════════════════════════════╝")
+	fmt.Fprintln(w)
 }
 
 func main() {
-	app := NewApp(
-		input.NewCLIReader(os.Stdin),
-		validator.NewGraphValidator(),
-		scheduler.NewWorkerScheduler(),
-		output.NewConsolePrinter(),
-	)
-
-	if err := app.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "\nError: %v\n", err)
-		os.Exit(1)
-	}
+	os.Exit(runCLI(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError is returned when the dependency graph contains a cycle.
+//
+// Cycles lists one concrete cycle per strongly connected group of tasks, in
+// dependency order with the first task repeated at the end
+// (e.g. A -> D -> F -> A means D depends on A, F on D and A on F).
+// Unvisited lists every task Kahn's algorithm could not order: the tasks on
+// cycles plus the tasks that depend on them. errors.Is matches it against
+// ErrCycle.
+type CycleError struct {
+	Message   string
+	Cycles    [][]string
+	Unvisited []string
+}
+
+func (e *CycleError) Error() string {
+	if len(e.Cycles) == 0 {
+		return e.Message
+	}
+	msg := fmt.Sprintf("%s: %s", e.Message, strings.Join(e.Cycles[0], " -> "))
+	if more := len(e.Cycles) - 1; more > 0 {
+		msg += fmt.Sprintf(" (and %d more cycle(s))", more)
+	}
+	return msg
+}
+
+// Is reports whether target is ErrCycle.
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// DetectCycle uses Kahn's algorithm (TopologicalSort) to detect cycles. If
+// not all tasks are ordered, the graph contains a cycle, and the concrete
+// cycles are extracted from the unordered tasks. Returns nil for a DAG.
+//
+// Undefined and self dependencies are ignored here, as by the other graph
+// methods; only edges between distinct, defined tasks can form a cycle.
+// Time complexity: O((V + E) log V).
+func (j *Job) DetectCycle() *CycleError {
+	_, unvisited := j.TopologicalSort()
+	if len(unvisited) == 0 {
+		return nil
+	}
+	return &CycleError{
+		Message:   "dependency graph contains a cycle, job cannot be completed",
+		Cycles:    findCycles(j.SuccessorMap(), unvisited),
+		Unvisited: unvisited,
+	}
+}
+
+// findCycles returns one shortest cycle for every strongly connected
+// component of the subgraph induced by nodes. Each cycle starts and ends at
+// the component's smallest ID; cycles are sorted by that ID.
+func findCycles(adjacency map[string][]string, nodes []string) [][]string {
+	var cycles [][]string
+	for _, component := range stronglyConnected(adjacency, nodes) {
+		if len(component) < 2 {
+			continue // a lone task depending on a cycle, not part of one
+		}
+		inComponent := make(map[string]bool, len(component))
+		for _, id := range component {
+			inComponent[id] = true
+		}
+		cycles = append(cycles, shortestCycle(adjacency, component[0], inComponent))
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// shortestCycle finds, by BFS inside the component, the shortest path from
+// start back to itself.
+func shortestCycle(adjacency map[string][]string, start string, inComponent map[string]bool) []string {
+	parent := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if !inComponent[next] {
+				continue
+			}
+			if next == start {
+				path := []string{start}
+				for id := current; id != start; id = parent[id] {
+					path = append(path, id)
+				}
+				// path is start, then the cycle backwards; reverse the tail.
+				for i, j := 1, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return append(path, start)
+			}
+			if _, seen := parent[next]; !seen {
+				parent[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil // unreachable for a strongly connected component of size > 1
+}
+
+// stronglyConnected returns the strongly connected components of the
+// subgraph induced by nodes (Tarjan's algorithm). Each component is sorted.
+func stronglyConnected(adjacency map[string][]string, nodes []string) [][]string {
+	inSubgraph := make(map[string]bool, len(nodes))
+	for _, id := range nodes {
+		inSubgraph[id] = true
+	}
+
+	index := make(map[string]int, len(nodes))
+	lowlink := make(map[string]int, len(nodes))
+	onStack := make(map[string]bool, len(nodes))
+	var stack []string
+	var components [][]string
+	counter := 0
+
+	var connect func(id string)
+	connect = func(id string) {
+		index[id] = counter
+		lowlink[id] = counter
+		counter++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range adjacency[id] {
+			if !inSubgraph[next] {
+				continue
+			}
+			if _, seen := index[next]; !seen {
+				connect(next)
+				lowlink[id] = min(lowlink[id], lowlink[next])
+			} else if onStack[next] {
+				lowlink[id] = min(lowlink[id], index[next])
+			}
+		}
+
+		if lowlink[id] == index[id] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, id := range nodes {
+		if _, seen := index[id]; !seen {
+			connect(id)
+		}
+	}
+	return components
+}
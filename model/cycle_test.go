@@ -0,0 +1,46 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Every strongly connected component is reported with one shortest cycle.
+func TestDetectCycle(t *testing.T) {
+	job := NewJob("J")
+	for _, task := range []*Task{
+		{ID: "a", Duration: 1, Dependencies: []string{"b"}},
+		{ID: "b", Duration: 1, Dependencies: []string{"a"}},
+		{ID: "x", Duration: 1, Dependencies: []string{"z"}},
+		{ID: "y", Duration: 1, Dependencies: []string{"x"}},
+		{ID: "z", Duration: 1, Dependencies: []string{"y", "x"}},
+		{ID: "ok", Duration: 1},
+	} {
+		if err := job.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cycleErr := job.DetectCycle()
+	if cycleErr == nil {
+		t.Fatal("DetectCycle() = nil, want a cycle")
+	}
+	got := make([]string, 0, len(cycleErr.Cycles))
+	for _, cycle := range cycleErr.Cycles {
+		got = append(got, strings.Join(cycle, " -> "))
+	}
+	want := []string{"a -> b -> a", "x -> z -> x"}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("cycles %q, want %q", got, want)
+	}
+	if !errors.Is(cycleErr, ErrCycle) {
+		t.Error("CycleError does not match ErrCycle")
+	}
+
+	delete(job.Tasks, "a")
+	delete(job.Tasks, "b")
+	delete(job.Tasks, "z")
+	if job.DetectCycle() != nil {
+		t.Error("DetectCycle() reports a cycle in a DAG")
+	}
+}
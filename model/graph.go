@@ -0,0 +1,279 @@
+package model
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrCycle is returned (wrapped) by the graph methods that need a DAG when
+// the dependencies form a cycle.
+var ErrCycle = errors.New("dependency graph contains a cycle")
+
+// The graph methods below treat each dependency as an edge from the
+// dependency to the dependent task. Dependencies on undefined tasks, on the
+// task itself, and repeated dependencies are ignored; the validator reports
+// them. All returned ID lists are sorted unless stated otherwise, so results
+// are deterministic.
+
+// Predecessors returns the tasks id directly depends on.
+func (j *Job) Predecessors(id string) []string {
+	task, ok := j.Tasks[id]
+	if !ok {
+		return nil
+	}
+	seen := make(map[string]bool, len(task.Dependencies))
+	var preds []string
+	for _, depID := range task.Dependencies {
+		if _, exists := j.Tasks[depID]; !exists || depID == id || seen[depID] {
+			continue
+		}
+		seen[depID] = true
+		preds = append(preds, depID)
+	}
+	sort.Strings(preds)
+	return preds
+}
+
+// Successors returns the tasks that directly depend on id. It scans every
+// task; use SuccessorMap when visiting many tasks.
+func (j *Job) Successors(id string) []string {
+	if _, ok := j.Tasks[id]; !ok {
+		return nil
+	}
+	var succs []string
+	for otherID, task := range j.Tasks {
+		if otherID != id && task.DependsOn(id) {
+			succs = append(succs, otherID)
+		}
+	}
+	sort.Strings(succs)
+	return succs
+}
+
+// SuccessorMap returns, for every task with dependents, the tasks that
+// directly depend on it. Build it once and reuse it when visiting many tasks.
+func (j *Job) SuccessorMap() map[string][]string {
+	successors := make(map[string][]string, len(j.Tasks))
+	for id := range j.Tasks {
+		for _, depID := range j.Predecessors(id) {
+			successors[depID] = append(successors[depID], id)
+		}
+	}
+	for id := range successors {
+		sort.Strings(successors[id])
+	}
+	return successors
+}
+
+// TopologicalSort orders the tasks so that every task comes after its
+// dependencies (Kahn's algorithm). Among tasks that are ready at the same
+// time the smallest ID comes first. Tasks that cannot be ordered because
+// they are on a cycle or depend on one are returned in unvisited; it is
+// empty for a DAG. Time complexity: O((V + E) log V).
+func (j *Job) TopologicalSort() (order, unvisited []string) {
+	successors := j.SuccessorMap()
+	indegree := make(map[string]int, len(j.Tasks))
+	queue := &idHeap{}
+	for id := range j.Tasks {
+		indegree[id] = len(j.Predecessors(id))
+		if indegree[id] == 0 {
+			*queue = append(*queue, id)
+		}
+	}
+	heap.Init(queue)
+
+	order = make([]string, 0, len(j.Tasks))
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(string)
+		order = append(order, current)
+
+		for _, next := range successors[current] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				heap.Push(queue, next)
+			}
+		}
+	}
+
+	for id, deg := range indegree {
+		if deg > 0 {
+			unvisited = append(unvisited, id)
+		}
+	}
+	sort.Strings(unvisited)
+	return order, unvisited
+}
+
+// TopologicalOrder is TopologicalSort for callers that need a DAG: it
+// returns an error wrapping ErrCycle when some tasks cannot be ordered.
+func (j *Job) TopologicalOrder() ([]string, error) {
+	order, unvisited := j.TopologicalSort()
+	if len(unvisited) > 0 {
+		return nil, fmt.Errorf("%w: tasks %s cannot be ordered", ErrCycle, strings.Join(unvisited, ", "))
+	}
+	return order, nil
+}
+
+// TopologicalLayers groups the tasks by depth: layer 0 holds the tasks
+// without dependencies, and every other task is one layer after its deepest
+// dependency. Tasks in the same layer never depend on each other.
+func (j *Job) TopologicalLayers() ([][]string, error) {
+	order, err := j.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	depth := make(map[string]int, len(order))
+	var layers [][]string
+	for _, id := range order {
+		d := 0
+		for _, depID := range j.Predecessors(id) {
+			d = max(d, depth[depID]+1)
+		}
+		depth[id] = d
+		if d == len(layers) {
+			layers = append(layers, nil)
+		}
+		layers[d] = append(layers[d], id)
+	}
+	for _, layer := range layers {
+		sort.Strings(layer)
+	}
+	return layers, nil
+}
+
+// Ancestors returns every task id depends on, directly or transitively.
+func (j *Job) Ancestors(id string) []string {
+	return reach(id, j.Predecessors)
+}
+
+// Descendants returns every task that depends on id, directly or
+// transitively. Use DescendantSets when asking for many tasks.
+func (j *Job) Descendants(id string) []string {
+	successors := j.SuccessorMap()
+	return reach(id, func(id string) []string { return successors[id] })
+}
+
+// FinishedAncestors returns every task that must finish before id starts:
+// the ancestors reached through links that make the dependency finish first
+// (see Link.FinishesFirst). Without typed links this is Ancestors. Use
+// FinishedAncestorSets when asking for many tasks.
+func (j *Job) FinishedAncestors(id string) []string {
+	return reach(id, func(id string) []string {
+		var preds []string
+		for _, depID := range j.Predecessors(id) {
+			if j.Tasks[id].Link(depID).FinishesFirst() {
+				preds = append(preds, depID)
+			}
+		}
+		return preds
+	})
+}
+
+// TransitiveClosure returns, for every task, the result of Ancestors: the
+// full set of tasks it depends on, directly or transitively. The sets are
+// computed together (see AncestorSets), not by one walk per task.
+func (j *Job) TransitiveClosure() map[string][]string {
+	sets := j.AncestorSets()
+	closure := make(map[string][]string, len(j.Tasks))
+	for id := range j.Tasks {
+		closure[id] = sets.Set(id)
+		if closure[id] == nil {
+			closure[id] = []string{}
+		}
+	}
+	return closure
+}
+
+// TransitiveReduction returns a copy of the job without the dependencies
+// that are implied by others: C -> A is dropped when C also depends on B
+// and B depends on A. The reduced job has the same ancestors for every
+// task. Kept dependencies stay in their original order; ignored ones
+// (undefined, self or repeated) are dropped. The receiver is not modified.
+//
+// With typed links only plain dependencies are dropped, and only when the
+// other path makes A finish before C starts (see FinishedAncestors).
+func (j *Job) TransitiveReduction() (*Job, error) {
+	if _, err := j.TopologicalOrder(); err != nil {
+		return nil, err
+	}
+	closure := j.FinishedAncestorSets()
+
+	reduced := NewJob(j.Name)
+	reduced.Pool = j.Pool
+	reduced.Resources = j.Resources
+	for id, task := range j.Tasks {
+		preds := j.Predecessors(id)
+		deps := []string{}
+		for _, depID := range task.Dependencies {
+			if !contains(preds, depID) || contains(deps, depID) {
+				continue
+			}
+			implied := false
+			for _, other := range preds {
+				if !task.Link(depID).IsPlain() || !task.Link(other).FinishesFirst() {
+					continue
+				}
+				if other != depID && closure.Contains(other, depID) {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				deps = append(deps, depID)
+			}
+		}
+		copied := *task
+		copied.Dependencies = deps
+		reduced.Tasks[id] = &copied
+	}
+	return reduced, nil
+}
+
+// reach returns the IDs reachable from start through next, excluding start
+// unless it lies on a cycle.
+func reach(start string, next func(id string) []string) []string {
+	seen := make(map[string]bool)
+	stack := append([]string(nil), next(start)...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		stack = append(stack, next(current)...)
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// idHeap is a min-heap of task IDs, used as the ready queue of
+// TopologicalSort.
+type idHeap []string
+
+func (h idHeap) Len() int           { return len(h) }
+func (h idHeap) Less(a, b int) bool { return h[a] < h[b] }
+func (h idHeap) Swap(a, b int)      { h[a], h[b] = h[b], h[a] }
+func (h *idHeap) Push(x any)        { *h = append(*h, x.(string)) }
+func (h *idHeap) Pop() any {
+	old := *h
+	id := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return id
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
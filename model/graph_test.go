@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// graphJob builds the case-study job plus an undefined, a self and a
+// repeated dependency, which the graph methods must ignore.
+func graphJob(t *testing.T) *Job {
+	t.Helper()
+	job := NewJob("J")
+	for _, task := range []*Task{
+		{ID: "A", Duration: 3},
+		{ID: "B", Duration: 2, Dependencies: []string{"B"}},
+		{ID: "C", Duration: 4, Dependencies: []string{"X"}},
+		{ID: "D", Duration: 5, Dependencies: []string{"A", "A"}},
+		{ID: "E", Duration: 2, Dependencies: []string{"C", "B"}},
+		{ID: "F", Duration: 3, Dependencies: []string{"D", "E", "A"}},
+	} {
+		if err := job.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return job
+}
+
+func TestJobGraph(t *testing.T) {
+	job := graphJob(t)
+	layers, err := job.TopologicalLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reduced, err := job.TransitiveReduction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, unvisited := job.TopologicalSort()
+
+	tests := []struct {
+		name string
+		got  any
+		want string
+	}{
+		{name: "predecessors", got: job.Predecessors("E"), want: "[B C]"},
+		{name: "ignored dependencies", got: job.Predecessors("B"), want: "[]"},
+		{name: "successors", got: job.Successors("A"), want: "[D F]"},
+		{name: "order", got: order, want: "[A B C D E F]"},
+		{name: "unvisited", got: unvisited, want: "[]"},
+		{name: "layers", got: layers, want: "[[A B C] [D E] [F]]"},
+		{name: "ancestors", got: job.Ancestors("F"), want: "[A B C D E]"},
+		{name: "descendants", got: job.Descendants("C"), want: "[E F]"},
+		{name: "reduced", got: reduced.Tasks["F"].Dependencies, want: "[D E]"},
+		{name: "reduced repeated", got: reduced.Tasks["D"].Dependencies, want: "[A]"},
+		{name: "original kept", got: job.Tasks["F"].Dependencies, want: "[D E A]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(tt.got); got != tt.want {
+			t.Errorf("%s: %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestJobGraphCycle(t *testing.T) {
+	job := NewJob("J")
+	for _, task := range []*Task{
+		{ID: "a", Duration: 1, Dependencies: []string{"c"}},
+		{ID: "b", Duration: 1, Dependencies: []string{"a"}},
+		{ID: "c", Duration: 1, Dependencies: []string{"b"}},
+		{ID: "d", Duration: 1, Dependencies: []string{"c"}},
+		{ID: "e", Duration: 1},
+	} {
+		if err := job.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	order, unvisited := job.TopologicalSort()
+	if fmt.Sprint(order) != "[e]" || fmt.Sprint(unvisited) != "[a b c d]" {
+		t.Errorf("TopologicalSort() = %v, %v; want [e], [a b c d]", order, unvisited)
+	}
+	if _, err := job.TopologicalOrder(); !errors.Is(err, ErrCycle) {
+		t.Errorf("TopologicalOrder() error = %v, want ErrCycle", err)
+	}
+	if _, err := job.TopologicalLayers(); !errors.Is(err, ErrCycle) {
+		t.Errorf("TopologicalLayers() error = %v, want ErrCycle", err)
+	}
+	if _, err := job.TransitiveReduction(); !errors.Is(err, ErrCycle) {
+		t.Errorf("TransitiveReduction() error = %v, want ErrCycle", err)
+	}
+}
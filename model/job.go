@@ -3,9 +3,16 @@ package model
 import "fmt"
 
 // Job holds a collection of Tasks organized as a DAG (Directed Acyclic Graph).
+//
+// Pool optionally describes the workers; when it is empty the job runs on
+// any number of identical workers of speed 1. Resources holds the capacity
+// of each renewable resource (e.g. "cpu": 8, "license": 2) that tasks can
+// demand through Task.Resources.
 type Job struct {
-	Name  string
-	Tasks map[string]*Task
+	Name      string
+	Tasks     map[string]*Task
+	Pool      []Worker
+	Resources map[string]int
 }
 
 // NewJob creates a new Job. Falls back to "Job" when name is empty.
@@ -42,6 +49,39 @@ func (j *Job) TaskCount() int {
 	return len(j.Tasks)
 }
 
+// HasRequirements reports whether any task requires worker tags.
+func (j *Job) HasRequirements() bool {
+	for _, task := range j.Tasks {
+		if len(task.Requires) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasResources reports whether any task demands resources.
+func (j *Job) HasResources() bool {
+	for _, task := range j.Tasks {
+		if len(task.Resources) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTypedLinks reports whether any dependency is not a plain
+// finish-to-start link without lag.
+func (j *Job) HasTypedLinks() bool {
+	for _, task := range j.Tasks {
+		for _, link := range task.Links {
+			if !link.IsPlain() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IndependentTasks returns all tasks that have no dependencies.
 func (j *Job) IndependentTasks() []*Task {
 	var result []*Task
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DependencyType says which ends of two tasks a dependency ties together.
+type DependencyType string
+
+// Dependency types, in the usual project-scheduling notation.
+const (
+	FinishToStart  DependencyType = "FS" // the task starts after the dependency finishes
+	StartToStart   DependencyType = "SS" // the task starts after the dependency starts
+	FinishToFinish DependencyType = "FF" // the task finishes after the dependency finishes
+	StartToFinish  DependencyType = "SF" // the task finishes after the dependency starts
+)
+
+// DependencyTypes lists the dependency types; FinishToStart is the default.
+func DependencyTypes() []DependencyType {
+	return []DependencyType{FinishToStart, StartToStart, FinishToFinish, StartToFinish}
+}
+
+// ParseDependencyType returns the type with the given name, ignoring case.
+// An empty name selects FinishToStart.
+func ParseDependencyType(name string) (DependencyType, error) {
+	normalized := DependencyType(strings.ToUpper(strings.TrimSpace(name)))
+	if normalized == "" {
+		return FinishToStart, nil
+	}
+	if !normalized.Valid() {
+		return "", fmt.Errorf("unknown dependency type '%s' (available: FS, SS, FF, SF)", name)
+	}
+	return normalized, nil
+}
+
+// Valid reports whether t is one of the four dependency types.
+func (t DependencyType) Valid() bool {
+	switch t {
+	case FinishToStart, StartToStart, FinishToFinish, StartToFinish:
+		return true
+	}
+	return false
+}
+
+// Link describes how a task depends on one of its dependencies: the ends
+// its Type ties together must be at least Lag time units apart. A negative
+// lag (a lead) lets the task's end come before the dependency's.
+//
+// The zero Link behaves as a plain finish-to-start dependency.
+type Link struct {
+	Type DependencyType
+	Lag  int
+}
+
+// linkNotation matches links written as "SS", "FF+2" or "FS-1".
+var linkNotation = regexp.MustCompile(`(?i)^(FS|SS|FF|SF)\s*([+-]\s*\d+)?$`)
+
+// ParseLink parses the compact notation used by the graph formats: a type
+// optionally followed by a signed lag, e.g. "SS+2" or "FF-1".
+func ParseLink(s string) (Link, error) {
+	m := linkNotation.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Link{}, fmt.Errorf("invalid link '%s', use e.g. \"SS\", \"FF+2\" or \"FS-1\"", s)
+	}
+	link := Link{Type: DependencyType(strings.ToUpper(m[1]))}
+	if m[2] != "" {
+		lag, err := strconv.Atoi(strings.ReplaceAll(m[2], " ", ""))
+		if err != nil {
+			return Link{}, fmt.Errorf("invalid lag in link '%s': %v", s, err)
+		}
+		link.Lag = lag
+	}
+	return link, nil
+}
+
+// String returns the link in the notation read by ParseLink.
+func (l Link) String() string {
+	t := l.kind()
+	if l.Lag == 0 {
+		return string(t)
+	}
+	return fmt.Sprintf("%s%+d", t, l.Lag)
+}
+
+// IsPlain reports whether l is finish-to-start without lag, the meaning of
+// an untyped dependency.
+func (l Link) IsPlain() bool {
+	return l.kind() == FinishToStart && l.Lag == 0
+}
+
+// FinishesFirst reports whether l makes the dependency finish before the
+// task starts, whatever the durations: finish-to-start with no lead.
+func (l Link) FinishesFirst() bool {
+	return l.kind() == FinishToStart && l.Lag >= 0
+}
+
+// Gap returns the least time from the dependency's finish to the task's
+// start that l allows, given both durations. It is negative when the task
+// may start before the dependency finishes. Every link thus becomes a
+// finish-to-start constraint once durations are known:
+//
+//	start >= dependency finish + Gap(dependency duration, duration)
+func (l Link) Gap(depDuration, duration int) int {
+	gap := l.Lag
+	switch l.kind() {
+	case StartToStart:
+		gap -= depDuration
+	case FinishToFinish:
+		gap -= duration
+	case StartToFinish:
+		gap -= depDuration + duration
+	}
+	return gap
+}
+
+func (l Link) kind() DependencyType {
+	if l.Type == "" {
+		return FinishToStart
+	}
+	return l.Type
+}
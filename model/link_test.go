@@ -0,0 +1,66 @@
+package model
+
+import "testing"
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Link
+		wantErr bool
+	}{
+		{in: "FS", want: Link{Type: FinishToStart}},
+		{in: "ss", want: Link{Type: StartToStart}},
+		{in: "FF+2", want: Link{Type: FinishToFinish, Lag: 2}},
+		{in: "SF-3", want: Link{Type: StartToFinish, Lag: -3}},
+		{in: " SS + 1 ", want: Link{Type: StartToStart, Lag: 1}},
+		{in: "", wantErr: true},
+		{in: "SX+2", wantErr: true},
+		{in: "SS+1.5", wantErr: true},
+		{in: "FF +", wantErr: true},
+		{in: "build", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLink(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseLink(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLink(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+		if back, err := ParseLink(got.String()); err != nil || back != got {
+			t.Errorf("ParseLink(%q) = %v, %v, want %v", got.String(), back, err, got)
+		}
+	}
+}
+
+// A dependency of duration 4 and a task of duration 2: the gap is the least
+// time from the dependency's finish to the task's start.
+func TestLinkGap(t *testing.T) {
+	tests := []struct {
+		link          Link
+		gap           int
+		plain, before bool
+	}{
+		{link: Link{}, gap: 0, plain: true, before: true},
+		{link: Link{Type: FinishToStart, Lag: 2}, gap: 2, before: true},
+		{link: Link{Type: FinishToStart, Lag: -1}, gap: -1},
+		{link: Link{Type: StartToStart, Lag: -1}, gap: -5},
+		{link: Link{Type: FinishToFinish, Lag: -1}, gap: -3},
+		{link: Link{Type: StartToFinish, Lag: -1}, gap: -7},
+		{link: Link{Type: StartToFinish, Lag: 6}, gap: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.link.Gap(4, 2); got != tt.gap {
+			t.Errorf("%v: Gap(4, 2) = %d, want %d", tt.link, got, tt.gap)
+		}
+		if got := tt.link.IsPlain(); got != tt.plain {
+			t.Errorf("%v: IsPlain() = %v, want %v", tt.link, got, tt.plain)
+		}
+		if got := tt.link.FinishesFirst(); got != tt.before {
+			t.Errorf("%v: FinishesFirst() = %v, want %v", tt.link, got, tt.before)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// Reachability holds, for every task of a job, the set of tasks it reaches
+// in one direction: its ancestors or its descendants. The sets are bitsets
+// built in one pass over the topological order, so building takes
+// O(V·E/64) time and O(V²/8) bytes, and every query afterwards is cheap.
+// Build it with Job.AncestorSets, Job.FinishedAncestorSets or
+// Job.DescendantSets; it does not follow later changes to the job.
+type Reachability struct {
+	ids   []string       // sorted task IDs; bit i stands for ids[i]
+	index map[string]int // position of each ID in ids
+	sets  []bitset       // sets[i] = tasks reached from ids[i]
+}
+
+// Contains reports whether other is in the set of id, e.g. whether id
+// depends on other for ancestor sets.
+func (r *Reachability) Contains(id, other string) bool {
+	i, ok := r.index[id]
+	k, found := r.index[other]
+	return ok && found && r.sets[i].has(k)
+}
+
+// Set returns the sorted IDs in the set of id.
+func (r *Reachability) Set(id string) []string {
+	i, ok := r.index[id]
+	if !ok {
+		return nil
+	}
+	var ids []string
+	r.sets[i].each(func(k int) { ids = append(ids, r.ids[k]) })
+	return ids
+}
+
+// Count returns the size of the set of id.
+func (r *Reachability) Count(id string) int {
+	i, ok := r.index[id]
+	if !ok {
+		return 0
+	}
+	return r.sets[i].count()
+}
+
+// AncestorSets returns, for every task, the tasks it depends on directly or
+// transitively (see Ancestors).
+func (j *Job) AncestorSets() *Reachability {
+	order, acyclic, predecessors := j.reachOrder()
+	return newReachability(j, order, acyclic, func(id string) []string { return predecessors[id] })
+}
+
+// FinishedAncestorSets returns, for every task, the result of
+// FinishedAncestors: the tasks that must finish before it starts.
+func (j *Job) FinishedAncestorSets() *Reachability {
+	order, acyclic, predecessors := j.reachOrder()
+	return newReachability(j, order, acyclic, func(id string) []string {
+		var preds []string
+		for _, depID := range predecessors[id] {
+			if j.Tasks[id].Link(depID).FinishesFirst() {
+				preds = append(preds, depID)
+			}
+		}
+		return preds
+	})
+}
+
+// DescendantSets returns, for every task, the tasks that depend on it
+// directly or transitively (see Descendants).
+func (j *Job) DescendantSets() *Reachability {
+	order, acyclic, _ := j.reachOrder()
+	for a, b := 0, len(order)-1; a < b; a, b = a+1, b-1 {
+		order[a], order[b] = order[b], order[a]
+	}
+	successors := j.SuccessorMap()
+	return newReachability(j, order, acyclic, func(id string) []string { return successors[id] })
+}
+
+// reachOrder returns the tasks in topological order, followed by those on
+// or behind a cycle, whether there were none of those, and every task's
+// predecessors.
+func (j *Job) reachOrder() ([]string, bool, map[string][]string) {
+	order, unvisited := j.TopologicalSort()
+	order = append(order, unvisited...)
+	predecessors := make(map[string][]string, len(j.Tasks))
+	for id := range j.Tasks {
+		predecessors[id] = j.Predecessors(id)
+	}
+	return order, len(unvisited) == 0, predecessors
+}
+
+// newReachability builds the sets by visiting order, in which next(id)
+// normally comes before id: each set is the union of the sets of next(id)
+// plus those tasks themselves. Tasks on cycles break that rule, so unless
+// the graph is acyclic the pass is repeated until nothing changes.
+func newReachability(j *Job, order []string, acyclic bool, next func(id string) []string) *Reachability {
+	r := &Reachability{
+		ids:   make([]string, 0, len(j.Tasks)),
+		index: make(map[string]int, len(j.Tasks)),
+	}
+	for id := range j.Tasks {
+		r.ids = append(r.ids, id)
+	}
+	sort.Strings(r.ids)
+	for i, id := range r.ids {
+		r.index[id] = i
+	}
+	r.sets = make([]bitset, len(r.ids))
+	for i := range r.sets {
+		r.sets[i] = newBitset(len(r.ids))
+	}
+
+	neighbours := make([][]int, len(r.ids))
+	for _, id := range order {
+		i := r.index[id]
+		for _, other := range next(id) {
+			neighbours[i] = append(neighbours[i], r.index[other])
+		}
+	}
+	for {
+		changed := false
+		for _, id := range order {
+			i := r.index[id]
+			for _, k := range neighbours[i] {
+				changed = r.sets[i].set(k) || changed
+				changed = r.sets[i].union(r.sets[k]) || changed
+			}
+		}
+		if acyclic || !changed {
+			break
+		}
+	}
+	return r
+}
+
+// bitset is a fixed-size set of small non-negative integers.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) has(i int) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+// set adds i and reports whether it was missing.
+func (b bitset) set(i int) bool {
+	if b.has(i) {
+		return false
+	}
+	b[i/64] |= 1 << (i % 64)
+	return true
+}
+
+// union adds every element of other and reports whether b changed.
+func (b bitset) union(other bitset) bool {
+	changed := false
+	for w, word := range other {
+		if merged := b[w] | word; merged != b[w] {
+			b[w] = merged
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (b bitset) count() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// each calls fn for every element in increasing order.
+func (b bitset) each(fn func(i int)) {
+	for w, word := range b {
+		for word != 0 {
+			fn(w*64 + bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+}
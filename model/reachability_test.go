@@ -0,0 +1,62 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// The bitset sets agree with the walks of Ancestors and Descendants, also
+// for tasks on and behind a cycle.
+func TestReachability(t *testing.T) {
+	cyclic := graphJob(t)
+	cyclic.Tasks["A"].Dependencies = []string{"F"}
+
+	for name, job := range map[string]*Job{"dag": graphJob(t), "cycle": cyclic} {
+		ancestors, descendants := job.AncestorSets(), job.DescendantSets()
+		for id := range job.Tasks {
+			if got, want := strings.Join(ancestors.Set(id), ","), strings.Join(job.Ancestors(id), ","); got != want {
+				t.Errorf("%s: ancestors of %s = %s, want %s", name, id, got, want)
+			}
+			if got, want := strings.Join(descendants.Set(id), ","), strings.Join(job.Descendants(id), ","); got != want {
+				t.Errorf("%s: descendants of %s = %s, want %s", name, id, got, want)
+			}
+			if got := ancestors.Count(id); got != len(job.Ancestors(id)) {
+				t.Errorf("%s: %d ancestor(s) of %s, want %d", name, got, id, len(job.Ancestors(id)))
+			}
+		}
+	}
+
+	sets := graphJob(t).AncestorSets()
+	if !sets.Contains("F", "A") || sets.Contains("A", "F") || sets.Contains("F", "X") {
+		t.Error("F must reach A only, and undefined tasks nothing")
+	}
+}
+
+// A long chain, where every task has all earlier ones as ancestors, is
+// reduced without one walk per task.
+func TestTransitiveReductionChain(t *testing.T) {
+	const n = 2000
+	job := NewJob("chain")
+	for i := 0; i < n; i++ {
+		var deps []string
+		if i > 0 {
+			deps = append(deps, fmt.Sprintf("t%04d", i-1))
+		}
+		if i > 1 {
+			deps = append(deps, fmt.Sprintf("t%04d", i-2))
+		}
+		if err := job.AddTask(&Task{ID: fmt.Sprintf("t%04d", i), Duration: 1, Dependencies: deps}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reduced, err := job.TransitiveReduction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, task := range reduced.Tasks {
+		if id != "t0000" && len(task.Dependencies) != 1 {
+			t.Fatalf("%s keeps dependencies %v, want only its predecessor", id, task.Dependencies)
+		}
+	}
+}
@@ -3,18 +3,123 @@ package model
 // TaskSchedule holds the computed timing for a single task.
 //
 // EarliestFinish = EarliestStart + Duration
+// LatestFinish   = min(LatestStart of successors), or the completion time
+// LatestStart    = LatestFinish - Duration
+// TotalFloat     = LatestStart - EarliestStart
+// FreeFloat      = min(EarliestStart of successors) - EarliestFinish
+//
+// TotalFloat is how long a task can slip without moving the job end date;
+// FreeFloat is how long it can slip without delaying any successor (tasks
+// without successors are measured against the completion time).
 type TaskSchedule struct {
 	TaskID         string
 	EarliestStart  int
 	EarliestFinish int
+	LatestStart    int
+	LatestFinish   int
+	TotalFloat     int
+	FreeFloat      int
+	WorkerID       int        // 1-based worker that runs the task; 0 when it did not run
+	Status         TaskStatus // outcome of an executed task; empty in a plan
+	Attempts       int        // number of times an executed task was started
+}
+
+// TaskStatus is the outcome of a task when a job is executed.
+type TaskStatus string
+
+// Task outcomes. A skipped task never started, because a dependency did not
+// succeed or the run was stopped; a cancelled task was killed while running.
+// A reused task succeeded in the earlier run that this one resumes.
+const (
+	StatusSucceeded TaskStatus = "succeeded"
+	StatusReused    TaskStatus = "reused"
+	StatusFailed    TaskStatus = "failed"
+	StatusSkipped   TaskStatus = "skipped"
+	StatusCancelled TaskStatus = "cancelled"
+)
+
+// TaskStatuses lists the outcomes in report order.
+var TaskStatuses = []TaskStatus{StatusSucceeded, StatusReused, StatusFailed, StatusCancelled, StatusSkipped}
+
+// Ran reports whether a task with this status occupied a worker: false for
+// skipped and reused tasks, true otherwise (including planned tasks).
+func (s TaskStatus) Ran() bool {
+	return s != StatusSkipped && s != StatusReused
+}
+
+// WorkerTimeline lists the tasks assigned to one worker, in start order.
+type WorkerTimeline struct {
+	WorkerID int
+	Tasks    []TaskSchedule
+}
+
+// BusyTime returns the total time the worker spends running tasks.
+func (wt WorkerTimeline) BusyTime() int {
+	busy := 0
+	for _, ts := range wt.Tasks {
+		busy += ts.EarliestFinish - ts.EarliestStart
+	}
+	return busy
 }
 
 // ScheduleResult contains the full output of the scheduling algorithm.
 type ScheduleResult struct {
-	JobName           string
-	Workers           int             // number of workers used
-	MinCompletionTime int
-	TaskSchedules     []TaskSchedule  // sorted by start time
-	ExecutionOrder    []string        // task IDs in order they were started
-	CriticalPath      []string        // longest path (only when workers >= task count)
+	JobName                string
+	Workers                int // number of workers used
+	MinCompletionTime      int
+	TaskSchedules          []TaskSchedule   // sorted by start time
+	ExecutionOrder         []string         // task IDs in order they were started
+	CriticalPath           []string         // first of CriticalPaths (only when workers >= task count)
+	CriticalPaths          [][]string       // every longest path, in lexicographic order
+	CriticalPathsTruncated bool             // CriticalPaths holds only the first of more paths
+	CriticalTasks          []string         // tasks with zero total float, sorted by ID
+	WorkerTimelines        []WorkerTimeline // by WorkerID: one per declared worker, or without a pool one per worker that runs a task
+	PriorityRule           string           // rule used to pick ready tasks (only when workers < task count)
+	Bounds                 LowerBounds      // individual lower bounds behind LowerBound
+	LowerBound             int              // proven lower bound on the completion time; 0 when not computed
+	Optimal                bool             // MinCompletionTime is proven to be the minimum
+	TimeUnit               string           // unit of all times: "" for abstract planning units, "ms" for measured runs
+	Pool                   []Worker         // the job's declared workers, if any
+	Resources              map[string]int   // capacities respected by the schedule, if any
+}
+
+// Unit returns the label printed after times, e.g. "unit(s)" or "ms".
+func (r *ScheduleResult) Unit() string {
+	if r.TimeUnit == "" {
+		return "unit(s)"
+	}
+	return r.TimeUnit
+}
+
+// LowerBounds holds lower bounds on the completion time of a job.
+// Any schedule, however clever, takes at least Best() units.
+type LowerBounds struct {
+	CriticalPath int // longest dependency chain
+	Work         int // total duration / workers, rounded up
+	Tag          int // work of the tasks requiring a tag / workers having it; 0 without tags
+	Resource     int // use of the scarcest resource / its capacity; 0 without resources
+	Energy       int // work that must fit between release and tail times
+}
+
+// Best returns the strongest (largest) bound.
+func (b LowerBounds) Best() int {
+	return max(b.CriticalPath, b.Work, b.Tag, b.Resource, b.Energy)
+}
+
+// OptimalityGap returns how many units MinCompletionTime may exceed the true
+// minimum, or -1 when no lower bound was computed.
+func (r *ScheduleResult) OptimalityGap() int {
+	if r.LowerBound <= 0 {
+		return -1
+	}
+	return r.MinCompletionTime - r.LowerBound
+}
+
+// OptimalityGapPercent returns the gap relative to the lower bound, or -1
+// when no lower bound was computed.
+func (r *ScheduleResult) OptimalityGapPercent() float64 {
+	if r.LowerBound <= 0 {
+		return -1
+	}
+	return 100 * float64(r.MinCompletionTime-r.LowerBound) / float64(r.LowerBound)
 }
@@ -1,13 +1,38 @@
 // Package model defines the core domain types for the job scheduling system.
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Task represents a single unit of work within a Job.
+//
+// Duration is the planning estimate. Command is only needed to execute the
+// job; tasks without one are treated as instant milestones when run.
+// Requires lists worker tags; only workers with all of them may run the task.
+// Resources holds the units of each job resource the task uses while it runs.
+// Links holds typed links keyed by dependency ID; a dependency without an
+// entry is finish-to-start with no lag.
 type Task struct {
 	ID           string
 	Duration     int
 	Dependencies []string
+	Command      *Command
+	Requires     []string
+	Resources    map[string]int
+	Links        map[string]Link
+}
+
+// Command is the process a task runs when the job is executed.
+type Command struct {
+	Args []string // program and arguments; Args[0] is looked up in PATH
+	Env  []string // KEY=VALUE entries added to the inherited environment
+	Dir  string   // working directory; empty means the current one
+
+	Retries int           // extra attempts after a failed one
+	Backoff time.Duration // wait before the first retry; doubled before each further one, up to a cap
+	Timeout time.Duration // limit for a single attempt; 0 means none
 }
 
 // NewTask creates a Task with the given parameters.
@@ -37,6 +62,11 @@ func (t *Task) HasDependencies() bool {
 	return len(t.Dependencies) > 0
 }
 
+// Link returns how the task depends on depID.
+func (t *Task) Link(depID string) Link {
+	return t.Links[depID]
+}
+
 // DependsOn checks whether the task depends on the given task ID.
 func (t *Task) DependsOn(taskID string) bool {
 	for _, dep := range t.Dependencies {
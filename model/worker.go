@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// Worker is one machine of a job's worker pool. Speed scales how long tasks
+// take on it: a task of duration d runs for ceil(d / Speed) units, so a
+// worker with speed 2 is twice as fast as the reference speed 1. Tags are
+// the capabilities it offers to tasks that require them (Task.Requires).
+type Worker struct {
+	ID    int // 1-based, as in TaskSchedule.WorkerID
+	Name  string
+	Speed float64
+	Tags  []string
+}
+
+// NewWorker creates a Worker. Returns an error if speed is not positive.
+// An empty name defaults to "W<id>".
+func NewWorker(id int, name string, speed float64) (Worker, error) {
+	if id <= 0 {
+		return Worker{}, fmt.Errorf("worker ID must be positive, got %d", id)
+	}
+	if !(speed > 0) || math.IsInf(speed, 0) {
+		return Worker{}, fmt.Errorf("worker %d: speed must be a positive number, got %g", id, speed)
+	}
+	if name == "" {
+		name = fmt.Sprintf("W%d", id)
+	}
+	return Worker{ID: id, Name: name, Speed: speed}, nil
+}
+
+// Duration returns how long a task of the given duration takes on w.
+// The result is rounded up and never below 1.
+func (w Worker) Duration(duration int) int {
+	// The epsilon keeps exact quotients such as 3 / 1.5 from rounding up.
+	d := int(math.Ceil(float64(duration)/w.Speed - 1e-9))
+	return max(d, 1)
+}
+
+// CanRun reports whether w has every tag the task requires.
+func (w Worker) CanRun(task *Task) bool {
+	for _, tag := range task.Requires {
+		if !w.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasTag reports whether w offers the given tag.
+func (w Worker) HasTag(tag string) bool {
+	for _, t := range w.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// UniformSpeed returns the speed shared by all workers of the pool, or false
+// when their speeds differ. An empty pool has the reference speed 1.
+func UniformSpeed(pool []Worker) (float64, bool) {
+	if len(pool) == 0 {
+		return 1, true
+	}
+	for _, w := range pool[1:] {
+		if w.Speed != pool[0].Speed {
+			return 0, false
+		}
+	}
+	return pool[0].Speed, true
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+
+	"wingie_case/model"
+)
+
+// csvHeader lists the CSV columns of schema version SchemaVersion.
+// Job-level values are repeated on every row so each row stands alone.
+// time_unit applies to every time column: "unit", or "ms" for an executed
+// job, whose status and attempts are otherwise empty.
+var csvHeader = []string{
+	"schema_version",
+	"job",
+	"workers",
+	"min_completion_time",
+	"time_unit",
+	"execution_index",
+	"task_id",
+	"worker_id",
+	"start",
+	"finish",
+	"duration",
+	"latest_start",
+	"latest_finish",
+	"total_float",
+	"free_float",
+	"critical",
+	"status",
+	"attempts",
+}
+
+// CSVPrinter writes one row per task, in execution order, after a header row.
+type CSVPrinter struct {
+	writer io.Writer
+}
+
+// NewCSVPrinter creates a CSV printer that writes to stdout.
+func NewCSVPrinter() *CSVPrinter {
+	return &CSVPrinter{writer: os.Stdout}
+}
+
+// NewCSVPrinterWithWriter creates a CSV printer that writes to the given writer.
+func NewCSVPrinterWithWriter(w io.Writer) *CSVPrinter {
+	return &CSVPrinter{writer: w}
+}
+
+// Print writes the header and the task rows.
+func (p *CSVPrinter) Print(result *model.ScheduleResult) {
+	doc := NewResultDocument(result)
+	cw := csv.NewWriter(p.writer)
+	_ = cw.Write(csvHeader)
+
+	for i, t := range doc.Tasks {
+		attempts := ""
+		if t.Attempts > 0 {
+			attempts = strconv.Itoa(t.Attempts)
+		}
+		_ = cw.Write([]string{
+			strconv.Itoa(doc.SchemaVersion),
+			doc.Job,
+			strconv.Itoa(doc.Workers),
+			strconv.Itoa(doc.MinCompletionTime),
+			doc.TimeUnit,
+			strconv.Itoa(i + 1),
+			t.ID,
+			strconv.Itoa(t.WorkerID),
+			strconv.Itoa(t.Start),
+			strconv.Itoa(t.Finish),
+			strconv.Itoa(t.Duration),
+			strconv.Itoa(t.LatestStart),
+			strconv.Itoa(t.LatestFinish),
+			strconv.Itoa(t.TotalFloat),
+			strconv.Itoa(t.FreeFloat),
+			strconv.FormatBool(t.Critical),
+			t.Status,
+			attempts,
+		})
+	}
+	cw.Flush()
+}
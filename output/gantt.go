@@ -0,0 +1,250 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"wingie_case/model"
+)
+
+// DefaultGanttWidth is used when the terminal width cannot be determined.
+const DefaultGanttWidth = 80
+
+// Bar characters used by GanttPrinter.
+const (
+	ganttCritical = '#' // task with zero total float
+	ganttNormal   = '=' // task that has float
+	ganttFloat    = '-' // total float of a task (per-task rows)
+	ganttIdle     = '.' // worker without a task (per-worker rows)
+	ganttFailed   = 'x' // executed task that failed or was cancelled
+)
+
+// GanttPrinter renders a ScheduleResult as a terminal Gantt chart.
+//
+// With unlimited workers there is one row per task, followed by its total
+// float. With limited workers there is one row per worker so that idle gaps
+// are visible. Critical tasks (zero total float) are drawn with '#'; in an
+// executed job, failed and cancelled tasks are drawn with 'x' and the task
+// rows name every outcome other than success.
+type GanttPrinter struct {
+	writer io.Writer
+	width  int
+}
+
+// NewGanttPrinter creates a Gantt printer that writes to stdout and fits the
+// terminal width reported by $COLUMNS.
+func NewGanttPrinter() *GanttPrinter {
+	return &GanttPrinter{writer: os.Stdout, width: TerminalWidth()}
+}
+
+// NewGanttPrinterWithWriter creates a Gantt printer that writes to w using
+// width columns. Non-positive widths fall back to DefaultGanttWidth.
+func NewGanttPrinterWithWriter(w io.Writer, width int) *GanttPrinter {
+	if width <= 0 {
+		width = DefaultGanttWidth
+	}
+	return &GanttPrinter{writer: w, width: width}
+}
+
+// TerminalWidth returns $COLUMNS when it is a positive integer, otherwise
+// DefaultGanttWidth.
+func TerminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return DefaultGanttWidth
+}
+
+// ganttRow is one labelled line of the chart.
+type ganttRow struct {
+	label  string
+	bar    []rune
+	suffix string
+}
+
+// Print renders the chart, a time axis and a legend.
+func (p *GanttPrinter) Print(result *model.ScheduleResult) {
+	w := p.writer
+	perWorker := result.Workers < len(result.TaskSchedules) && len(result.WorkerTimelines) > 0
+
+	labelWidth := 4
+	for _, ts := range result.TaskSchedules {
+		labelWidth = max(labelWidth, len(ts.TaskID))
+	}
+	for _, wt := range result.WorkerTimelines {
+		labelWidth = max(labelWidth, len(workerLabel(wt.WorkerID)))
+	}
+
+	// "  label |bar| suffix": reserve room for the suffix ("12-340" or "busy 340").
+	total := len(strconv.Itoa(result.MinCompletionTime))
+	suffixWidth := max(2*total+1, len("busy ")+total)
+	cols := p.width - labelWidth - suffixWidth - 5
+	if cols < 10 {
+		cols = 10
+	}
+	scale := newGanttScale(result.MinCompletionTime, cols)
+
+	var rows []ganttRow
+	if perWorker {
+		rows = p.workerRows(result, scale)
+	} else {
+		rows = p.taskRows(result, scale)
+	}
+
+	fmt.Fprintf(w, "\n  Gantt chart: %s (%d worker(s), %d %s)\n\n",
+		result.JobName, result.Workers, result.MinCompletionTime, result.Unit())
+	for _, row := range rows {
+		fmt.Fprintf(w, "  %-*s |%s| %s\n", labelWidth, row.label, string(row.bar), row.suffix)
+	}
+
+	axis, labels := scale.axis()
+	fmt.Fprintf(w, "  %-*s +%s+\n", labelWidth, "", axis)
+	fmt.Fprintf(w, "  %-*s  %s\n\n", labelWidth, "", labels)
+
+	legend := fmt.Sprintf("  %c critical  %c other  %c float", ganttCritical, ganttNormal, ganttFloat)
+	if perWorker {
+		legend = fmt.Sprintf("  %c critical  %c other  %c idle", ganttCritical, ganttNormal, ganttIdle)
+	}
+	if hasFailures(result) {
+		legend += fmt.Sprintf("  %c failed/cancelled", ganttFailed)
+	}
+	fmt.Fprintln(w, legend)
+}
+
+// hasFailures reports whether any task of an executed job failed or was cancelled.
+func hasFailures(result *model.ScheduleResult) bool {
+	for _, ts := range result.TaskSchedules {
+		if ts.Status == model.StatusFailed || ts.Status == model.StatusCancelled {
+			return true
+		}
+	}
+	return false
+}
+
+// taskRows draws one row per task with its total float after the bar.
+func (p *GanttPrinter) taskRows(result *model.ScheduleResult, scale ganttScale) []ganttRow {
+	rows := make([]ganttRow, 0, len(result.TaskSchedules))
+	for _, ts := range result.TaskSchedules {
+		bar := []rune(strings.Repeat(" ", scale.cols))
+		from, to := scale.span(ts.EarliestStart, ts.EarliestFinish)
+		fill(bar, from, to, barChar(ts))
+		if ts.TotalFloat > 0 {
+			_, floatEnd := scale.span(ts.EarliestFinish, ts.EarliestFinish+ts.TotalFloat)
+			fill(bar, to, floatEnd, ganttFloat)
+		}
+		suffix := fmt.Sprintf("%d-%d", ts.EarliestStart, ts.EarliestFinish)
+		if ts.Status != "" && ts.Status != model.StatusSucceeded {
+			suffix += " " + string(ts.Status)
+		}
+		rows = append(rows, ganttRow{label: ts.TaskID, bar: bar, suffix: suffix})
+	}
+	return rows
+}
+
+// workerRows draws one row per worker; each task segment starts with its ID.
+func (p *GanttPrinter) workerRows(result *model.ScheduleResult, scale ganttScale) []ganttRow {
+	rows := make([]ganttRow, 0, len(result.WorkerTimelines))
+	for _, wt := range result.WorkerTimelines {
+		bar := []rune(strings.Repeat(string(ganttIdle), scale.cols))
+		for _, ts := range wt.Tasks {
+			from, to := scale.span(ts.EarliestStart, ts.EarliestFinish)
+			fill(bar, from, to, barChar(ts))
+			id := []rune(ts.TaskID)
+			if len(id) <= to-from {
+				copy(bar[from:], id)
+			}
+		}
+		rows = append(rows, ganttRow{
+			label:  workerLabel(wt.WorkerID),
+			bar:    bar,
+			suffix: fmt.Sprintf("busy %d", wt.BusyTime()),
+		})
+	}
+	return rows
+}
+
+func barChar(ts model.TaskSchedule) rune {
+	if ts.Status == model.StatusFailed || ts.Status == model.StatusCancelled {
+		return ganttFailed
+	}
+	if ts.TotalFloat == 0 {
+		return ganttCritical
+	}
+	return ganttNormal
+}
+
+func fill(bar []rune, from, to int, ch rune) {
+	for i := from; i < to && i < len(bar); i++ {
+		bar[i] = ch
+	}
+}
+
+// ganttScale maps time units onto chart columns.
+type ganttScale struct {
+	total int // completion time
+	cols  int // chart width in characters
+}
+
+// newGanttScale fits total time units into at most maxCols columns, using a
+// whole number of columns per unit when the job is short.
+func newGanttScale(total, maxCols int) ganttScale {
+	if total <= 0 {
+		return ganttScale{total: 1, cols: maxCols}
+	}
+	if total <= maxCols {
+		return ganttScale{total: total, cols: total * (maxCols / total)}
+	}
+	return ganttScale{total: total, cols: maxCols}
+}
+
+// col returns the column where time t begins.
+func (s ganttScale) col(t int) int {
+	return t * s.cols / s.total
+}
+
+// span returns the columns [from, to) covering [start, finish); non-empty
+// intervals always get at least one column.
+func (s ganttScale) span(start, finish int) (int, int) {
+	from, to := s.col(start), s.col(finish)
+	if to <= from && finish > start {
+		to = from + 1
+	}
+	if to > s.cols {
+		to = s.cols
+	}
+	if from > to {
+		from = to
+	}
+	return from, to
+}
+
+// axis returns a ruler with tick marks and the matching time labels.
+func (s ganttScale) axis() (string, string) {
+	ruler := []rune(strings.Repeat("-", s.cols))
+	labels := []rune(strings.Repeat(" ", s.cols+8))
+
+	step := 1
+	for s.col(step) < 8 && step < s.total {
+		step++
+	}
+	next := 0 // first free label column
+	for t := 0; t <= s.total; t += step {
+		c := s.col(t)
+		if c < s.cols {
+			ruler[c] = '|'
+		}
+		text := strconv.Itoa(t)
+		if c >= next && c+len(text) <= len(labels) {
+			copy(labels[c:], []rune(text))
+			next = c + len(text) + 1
+		}
+	}
+	end := strconv.Itoa(s.total)
+	if c := s.cols - len(end) + 1; c >= next {
+		copy(labels[c:], []rune(end))
+	}
+	return string(ruler), strings.TrimRight(string(labels), " ")
+}
@@ -0,0 +1,107 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+// ganttResult is the case-study plan: per task with six workers, per worker
+// with two.
+func ganttResult(workers int) *model.ScheduleResult {
+	result := &model.ScheduleResult{JobName: "J", Workers: workers, MinCompletionTime: 11}
+	if workers >= 6 {
+		result.TaskSchedules = []model.TaskSchedule{
+			{TaskID: "A", EarliestStart: 0, EarliestFinish: 3},
+			{TaskID: "B", EarliestStart: 0, EarliestFinish: 2, TotalFloat: 4},
+			{TaskID: "C", EarliestStart: 0, EarliestFinish: 4, TotalFloat: 2},
+			{TaskID: "D", EarliestStart: 3, EarliestFinish: 8},
+			{TaskID: "E", EarliestStart: 4, EarliestFinish: 6, TotalFloat: 2},
+			{TaskID: "F", EarliestStart: 8, EarliestFinish: 11},
+		}
+		return result
+	}
+	w1 := []model.TaskSchedule{
+		{TaskID: "A", EarliestStart: 0, EarliestFinish: 3, WorkerID: 1},
+		{TaskID: "D", EarliestStart: 3, EarliestFinish: 8, WorkerID: 1},
+		{TaskID: "F", EarliestStart: 8, EarliestFinish: 11, WorkerID: 1},
+	}
+	w2 := []model.TaskSchedule{
+		{TaskID: "B", EarliestStart: 0, EarliestFinish: 2, TotalFloat: 4, WorkerID: 2},
+		{TaskID: "C", EarliestStart: 2, EarliestFinish: 6, WorkerID: 2},
+		{TaskID: "E", EarliestStart: 6, EarliestFinish: 8, WorkerID: 2},
+	}
+	result.TaskSchedules = append(append([]model.TaskSchedule(nil), w1...), w2...)
+	result.WorkerTimelines = []model.WorkerTimeline{{WorkerID: 1, Tasks: w1}, {WorkerID: 2, Tasks: w2}}
+	return result
+}
+
+func TestGanttPrinter(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		width   int
+		want    []string
+	}{
+		{
+			name:    "per task",
+			workers: 6,
+			width:   40,
+			want: []string{
+				"  A    |######                | 0-3",
+				"  B    |====--------          | 0-2",
+				"  F    |                ######| 8-11",
+				"# critical  = other  - float",
+			},
+		},
+		{
+			name:    "per worker",
+			workers: 2,
+			width:   40,
+			want: []string{
+				"  W1   |A#####D#########F#####| busy 11",
+				"  W2   |B===C#######E###......| busy 8",
+				"# critical  = other  . idle",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewGanttPrinterWithWriter(&buf, tt.width).Print(ganttResult(tt.workers))
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output lacks %q:\n%s", want, out)
+				}
+			}
+			for _, line := range strings.Split(out, "\n") {
+				if strings.ContainsAny(line, "|+") && len(line) > tt.width {
+					t.Errorf("line is %d columns wide, want at most %d: %q", len(line), tt.width, line)
+				}
+			}
+		})
+	}
+}
+
+// Long jobs are compressed to the available width; every task still gets a
+// column.
+func TestGanttScale(t *testing.T) {
+	scale := newGanttScale(1000, 50)
+	if scale.cols != 50 {
+		t.Errorf("cols %d, want 50", scale.cols)
+	}
+	if from, to := scale.span(500, 501); to-from != 1 {
+		t.Errorf("span(500, 501) = [%d, %d), want one column", from, to)
+	}
+	if from, to := scale.span(0, 1000); from != 0 || to != 50 {
+		t.Errorf("span(0, 1000) = [%d, %d), want [0, 50)", from, to)
+	}
+
+	short := newGanttScale(11, 50)
+	if short.cols != 44 {
+		t.Errorf("cols %d, want 44 (4 columns per unit)", short.cols)
+	}
+}
@@ -0,0 +1,216 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"wingie_case/model"
+)
+
+// GraphPrinter draws the dependency graph of a job. Unlike Printer it needs
+// the job itself; result is optional and adds schedule times and
+// critical-path styling when present.
+type GraphPrinter interface {
+	PrintGraph(job *model.Job, result *model.ScheduleResult)
+}
+
+// graphNode and graphEdge are the format-independent view of a job that
+// DOTPrinter and MermaidPrinter render.
+type graphNode struct {
+	id       string
+	label    []string // lines
+	critical bool
+}
+
+type graphEdge struct {
+	from, to string
+	link     model.Link
+	critical bool
+}
+
+// buildGraph lists nodes in ID order and edges by dependency, then
+// dependent. Nodes carry the duration and, with a result, EST/EFT. As in the
+// Gantt chart, critical tasks are those with zero total float; an edge is
+// critical when it links two critical tasks and the dependent task starts
+// exactly when the link allows, i.e. with no slack after the dependency.
+func buildGraph(job *model.Job, result *model.ScheduleResult) ([]graphNode, []graphEdge) {
+	schedules := make(map[string]model.TaskSchedule)
+	if result != nil {
+		for _, ts := range result.TaskSchedules {
+			schedules[ts.TaskID] = ts
+		}
+	}
+	isCritical := func(id string) bool {
+		ts, ok := schedules[id]
+		return ok && ts.TotalFloat == 0
+	}
+
+	ids := make([]string, 0, job.TaskCount())
+	for id := range job.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	successors := job.SuccessorMap()
+	nodes := make([]graphNode, 0, len(ids))
+	var edges []graphEdge
+	for _, id := range ids {
+		label := []string{fmt.Sprintf("%s (%d)", id, job.Tasks[id].Duration)}
+		if ts, ok := schedules[id]; ok {
+			label = append(label, fmt.Sprintf("EST %d / EFT %d", ts.EarliestStart, ts.EarliestFinish))
+		}
+		nodes = append(nodes, graphNode{id: id, label: label, critical: isCritical(id)})
+		for _, next := range successors[id] {
+			link := job.Tasks[next].Link(id)
+			dep, ts := schedules[id], schedules[next]
+			gap := link.Gap(dep.EarliestFinish-dep.EarliestStart, ts.EarliestFinish-ts.EarliestStart)
+			critical := isCritical(id) && isCritical(next) && dep.EarliestFinish+gap == ts.EarliestStart
+			edges = append(edges, graphEdge{from: id, to: next, link: link, critical: critical})
+		}
+	}
+	return nodes, edges
+}
+
+// graphTitle describes the job and, with a result, its completion time.
+func graphTitle(job *model.Job, result *model.ScheduleResult) string {
+	if result == nil {
+		return job.Name
+	}
+	return fmt.Sprintf("%s: %d %s on %d worker(s)", job.Name, result.MinCompletionTime, result.Unit(), result.Workers)
+}
+
+// DOTPrinter writes the job as a Graphviz digraph, read left to right.
+// Critical tasks and edges are drawn in bold red, and typed links are
+// labelled as in "SS+2". With a result the worker count is kept as a
+// "workers" graph attribute, which Graphviz ignores.
+type DOTPrinter struct {
+	writer io.Writer
+}
+
+// NewDOTPrinter creates a DOT printer that writes to stdout.
+func NewDOTPrinter() *DOTPrinter {
+	return &DOTPrinter{writer: os.Stdout}
+}
+
+// NewDOTPrinterWithWriter creates a DOT printer that writes to w.
+func NewDOTPrinterWithWriter(w io.Writer) *DOTPrinter {
+	return &DOTPrinter{writer: w}
+}
+
+// PrintGraph writes the digraph.
+func (p *DOTPrinter) PrintGraph(job *model.Job, result *model.ScheduleResult) {
+	w := p.writer
+	nodes, edges := buildGraph(job, result)
+
+	fmt.Fprintf(w, "digraph %s {\n", dotQuote(job.Name))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintf(w, "  label=%s;\n", dotQuote(graphTitle(job, result)))
+	fmt.Fprintln(w, "  labelloc=t;")
+	if result != nil {
+		fmt.Fprintf(w, "  workers=%d;\n", result.Workers)
+	}
+	fmt.Fprintln(w, "  node [shape=box, style=rounded];")
+	for _, n := range nodes {
+		style := ""
+		if n.critical {
+			style = ", color=red, penwidth=2"
+		}
+		fmt.Fprintf(w, "  %s [label=%s%s];\n", dotQuote(n.id), dotQuote(strings.Join(n.label, "\n")), style)
+	}
+	for _, e := range edges {
+		var attrs []string
+		if !e.link.IsPlain() {
+			attrs = append(attrs, "label="+dotQuote(e.link.String()))
+		}
+		if e.critical {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		style := ""
+		if len(attrs) > 0 {
+			style = " [" + strings.Join(attrs, ", ") + "]"
+		}
+		fmt.Fprintf(w, "  %s -> %s%s;\n", dotQuote(e.from), dotQuote(e.to), style)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// dotQuote returns s as a DOT string literal.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// MermaidPrinter writes the job as a Mermaid flowchart, read left to right.
+// Task IDs are replaced by generated node names (t0, t1, ...) so any ID is
+// safe; the ID appears in the node label. Critical tasks and edges are
+// styled with the "critical" class and linkStyle, and typed links carry a
+// "|SS+2|" label. The job name and worker count are kept in "%% name:" and
+// "%% workers:" comments.
+type MermaidPrinter struct {
+	writer io.Writer
+}
+
+// NewMermaidPrinter creates a Mermaid printer that writes to stdout.
+func NewMermaidPrinter() *MermaidPrinter {
+	return &MermaidPrinter{writer: os.Stdout}
+}
+
+// NewMermaidPrinterWithWriter creates a Mermaid printer that writes to w.
+func NewMermaidPrinterWithWriter(w io.Writer) *MermaidPrinter {
+	return &MermaidPrinter{writer: w}
+}
+
+// PrintGraph writes the flowchart.
+func (p *MermaidPrinter) PrintGraph(job *model.Job, result *model.ScheduleResult) {
+	w := p.writer
+	nodes, edges := buildGraph(job, result)
+
+	fmt.Fprintln(w, "---")
+	fmt.Fprintf(w, "title: %s\n", strconv.Quote(graphTitle(job, result)))
+	fmt.Fprintln(w, "---")
+	fmt.Fprintf(w, "%%%% name: %s\n", job.Name)
+	if result != nil {
+		fmt.Fprintf(w, "%%%% workers: %d\n", result.Workers)
+	}
+	fmt.Fprintln(w, "flowchart LR")
+
+	names := make(map[string]string, len(nodes))
+	var criticalNodes []string
+	for i, n := range nodes {
+		names[n.id] = fmt.Sprintf("t%d", i)
+		fmt.Fprintf(w, "  %s[%s]\n", names[n.id], mermaidQuote(strings.Join(n.label, "<br/>")))
+		if n.critical {
+			criticalNodes = append(criticalNodes, names[n.id])
+		}
+	}
+
+	var criticalLinks []string
+	for i, e := range edges {
+		label := ""
+		if !e.link.IsPlain() {
+			label = "|" + e.link.String() + "|"
+		}
+		fmt.Fprintf(w, "  %s -->%s %s\n", names[e.from], label, names[e.to])
+		if e.critical {
+			criticalLinks = append(criticalLinks, fmt.Sprint(i))
+		}
+	}
+
+	if len(criticalNodes) > 0 {
+		fmt.Fprintln(w, "  classDef critical stroke:#d00,stroke-width:3px")
+		fmt.Fprintf(w, "  class %s critical\n", strings.Join(criticalNodes, ","))
+	}
+	if len(criticalLinks) > 0 {
+		fmt.Fprintf(w, "  linkStyle %s stroke:#d00,stroke-width:3px\n", strings.Join(criticalLinks, ","))
+	}
+}
+
+// mermaidQuote returns s as a quoted Mermaid label; double quotes are
+// written as the #quot; entity.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
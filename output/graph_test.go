@@ -0,0 +1,129 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+// graphJob is the case-study job with an ID that needs quoting.
+func graphJob(t *testing.T) *model.Job {
+	t.Helper()
+	job := model.NewJob(`J "1"`)
+	for _, task := range []*model.Task{
+		{ID: "A", Duration: 3},
+		{ID: "B", Duration: 2},
+		{ID: "C", Duration: 4},
+		{ID: "D", Duration: 5, Dependencies: []string{"A"}},
+		{ID: "E", Duration: 2, Dependencies: []string{"B", "C"}},
+		{ID: "F", Duration: 3, Dependencies: []string{"D", "E"}},
+	} {
+		if err := job.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return job
+}
+
+// graphSchedule is the CPM plan of graphJob.
+func graphSchedule() *model.ScheduleResult {
+	return &model.ScheduleResult{
+		JobName:           `J "1"`,
+		Workers:           6,
+		MinCompletionTime: 11,
+		TaskSchedules: []model.TaskSchedule{
+			{TaskID: "A", EarliestStart: 0, EarliestFinish: 3},
+			{TaskID: "B", EarliestStart: 0, EarliestFinish: 2, TotalFloat: 4},
+			{TaskID: "C", EarliestStart: 0, EarliestFinish: 4, TotalFloat: 2},
+			{TaskID: "D", EarliestStart: 3, EarliestFinish: 8},
+			{TaskID: "E", EarliestStart: 4, EarliestFinish: 6, TotalFloat: 2},
+			{TaskID: "F", EarliestStart: 8, EarliestFinish: 11},
+		},
+	}
+}
+
+func TestGraphPrinters(t *testing.T) {
+	tests := []struct {
+		name    string
+		printer func(w *bytes.Buffer) GraphPrinter
+		result  *model.ScheduleResult
+		want    []string
+		not     []string
+	}{
+		{
+			name:    "dot",
+			printer: func(w *bytes.Buffer) GraphPrinter { return NewDOTPrinterWithWriter(w) },
+			want:    []string{`digraph "J \"1\"" {`, `label="J \"1\"";`, `"A" [label="A (3)"];`, `"A" -> "D";`, `"E" -> "F";`},
+			not:     []string{"color=red", "EST"},
+		},
+		{
+			name:    "dot with schedule",
+			printer: func(w *bytes.Buffer) GraphPrinter { return NewDOTPrinterWithWriter(w) },
+			result:  graphSchedule(),
+			want: []string{
+				`label="J \"1\": 11 unit(s) on 6 worker(s)";`,
+				`"A" [label="A (3)\nEST 0 / EFT 3", color=red, penwidth=2];`,
+				`"B" [label="B (2)\nEST 0 / EFT 2"];`,
+				`"A" -> "D" [color=red, penwidth=2];`,
+				`"C" -> "E";`,
+			},
+		},
+		{
+			name:    "mermaid",
+			printer: func(w *bytes.Buffer) GraphPrinter { return NewMermaidPrinterWithWriter(w) },
+			want:    []string{`title: "J \"1\""`, "flowchart LR", `t0["A (3)"]`, "t0 --> t3", "t4 --> t5"},
+			not:     []string{"classDef", "linkStyle"},
+		},
+		{
+			name:    "mermaid with schedule",
+			printer: func(w *bytes.Buffer) GraphPrinter { return NewMermaidPrinterWithWriter(w) },
+			result:  graphSchedule(),
+			want: []string{
+				`t3["D (5)<br/>EST 3 / EFT 8"]`,
+				"class t0,t3,t5 critical",
+				"linkStyle 0,3 stroke:#d00",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.printer(&buf).PrintGraph(graphJob(t), tt.result)
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output lacks %q:\n%s", want, buf.String())
+				}
+			}
+			for _, not := range tt.not {
+				if strings.Contains(buf.String(), not) {
+					t.Errorf("output contains %q:\n%s", not, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGraphPrintersLinks(t *testing.T) {
+	job := graphJob(t)
+	job.Tasks["D"].Links = map[string]model.Link{"A": {Type: model.StartToStart, Lag: 1}}
+	job.Tasks["F"].Links = map[string]model.Link{"E": {Type: model.FinishToStart}}
+
+	var dot, mermaid bytes.Buffer
+	NewDOTPrinterWithWriter(&dot).PrintGraph(job, nil)
+	NewMermaidPrinterWithWriter(&mermaid).PrintGraph(job, nil)
+	for _, tt := range []struct {
+		out  string
+		want []string
+	}{
+		{dot.String(), []string{`"A" -> "D" [label="SS+1"];`, `"E" -> "F";`}},
+		{mermaid.String(), []string{"t0 -->|SS+1| t3", "t4 --> t5"}},
+	} {
+		for _, want := range tt.want {
+			if !strings.Contains(tt.out, want) {
+				t.Errorf("output lacks %q:\n%s", want, tt.out)
+			}
+		}
+	}
+}
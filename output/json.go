@@ -0,0 +1,187 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"wingie_case/model"
+)
+
+// SchemaVersion identifies the layout of the JSON and CSV exports.
+// It only changes when a field is renamed, removed or changes meaning;
+// new fields may be added within the same version. Version 2 added
+// time_unit, since the times of an executed job are in milliseconds.
+const SchemaVersion = 2
+
+// ResultDocument is the machine-readable form of a ScheduleResult.
+// Slices are never nil so that they encode as [] rather than null.
+type ResultDocument struct {
+	SchemaVersion          int                `json:"schema_version"`
+	Job                    string             `json:"job"`
+	Workers                int                `json:"workers"`
+	Pool                   []WorkerDocument   `json:"pool,omitempty"`
+	Resources              map[string]int     `json:"resources,omitempty"`
+	PriorityRule           string             `json:"priority_rule,omitempty"`
+	MinCompletionTime      int                `json:"min_completion_time"`
+	TimeUnit               string             `json:"time_unit"` // "unit" or "ms"
+	LowerBound             int                `json:"lower_bound,omitempty"`
+	LowerBounds            *BoundsDocument    `json:"lower_bounds,omitempty"`
+	Optimal                bool               `json:"optimal"`
+	ExecutionOrder         []string           `json:"execution_order"`
+	CriticalPath           []string           `json:"critical_path"`
+	CriticalPaths          [][]string         `json:"critical_paths"`
+	CriticalPathsTruncated bool               `json:"critical_paths_truncated,omitempty"`
+	CriticalTasks          []string           `json:"critical_tasks"`
+	Tasks                  []TaskDocument     `json:"tasks"`
+	WorkerTimelines        []TimelineDocument `json:"worker_timelines"`
+}
+
+// TaskDocument is the machine-readable form of a TaskSchedule.
+type TaskDocument struct {
+	ID           string `json:"id"`
+	WorkerID     int    `json:"worker_id"`
+	Start        int    `json:"start"`
+	Finish       int    `json:"finish"`
+	Duration     int    `json:"duration"`
+	LatestStart  int    `json:"latest_start"`
+	LatestFinish int    `json:"latest_finish"`
+	TotalFloat   int    `json:"total_float"`
+	FreeFloat    int    `json:"free_float"`
+	Critical     bool   `json:"critical"`
+	Status       string `json:"status,omitempty"`   // only for executed jobs
+	Attempts     int    `json:"attempts,omitempty"` // only for executed jobs
+}
+
+// WorkerDocument describes one worker of the job's pool.
+type WorkerDocument struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Speed float64  `json:"speed"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// BoundsDocument lists the individual lower bounds on the completion time.
+type BoundsDocument struct {
+	CriticalPath int `json:"critical_path"`
+	Work         int `json:"work"`
+	Tag          int `json:"tags,omitempty"`
+	Resource     int `json:"resources,omitempty"`
+	Energy       int `json:"energy"`
+}
+
+// TimelineDocument lists the tasks run by one worker, in start order.
+type TimelineDocument struct {
+	WorkerID int      `json:"worker_id"`
+	BusyTime int      `json:"busy_time"`
+	Tasks    []string `json:"tasks"`
+}
+
+// NewResultDocument converts a ScheduleResult into its export form.
+func NewResultDocument(result *model.ScheduleResult) *ResultDocument {
+	critical := make(map[string]bool, len(result.CriticalTasks))
+	for _, id := range result.CriticalTasks {
+		critical[id] = true
+	}
+
+	doc := &ResultDocument{
+		SchemaVersion:          SchemaVersion,
+		Job:                    result.JobName,
+		Workers:                result.Workers,
+		PriorityRule:           result.PriorityRule,
+		MinCompletionTime:      result.MinCompletionTime,
+		TimeUnit:               timeUnit(result),
+		LowerBound:             result.LowerBound,
+		Optimal:                result.Optimal,
+		ExecutionOrder:         nonNil(result.ExecutionOrder),
+		CriticalPath:           nonNil(result.CriticalPath),
+		CriticalPaths:          make([][]string, 0, len(result.CriticalPaths)),
+		CriticalPathsTruncated: result.CriticalPathsTruncated,
+		CriticalTasks:          nonNil(result.CriticalTasks),
+		Tasks:                  make([]TaskDocument, 0, len(result.TaskSchedules)),
+		WorkerTimelines:        make([]TimelineDocument, 0, len(result.WorkerTimelines)),
+	}
+	if result.LowerBound > 0 {
+		doc.LowerBounds = &BoundsDocument{
+			CriticalPath: result.Bounds.CriticalPath,
+			Work:         result.Bounds.Work,
+			Tag:          result.Bounds.Tag,
+			Resource:     result.Bounds.Resource,
+			Energy:       result.Bounds.Energy,
+		}
+	}
+	doc.Resources = result.Resources
+	for _, wk := range result.Pool {
+		doc.Pool = append(doc.Pool, WorkerDocument{ID: wk.ID, Name: wk.Name, Speed: wk.Speed, Tags: wk.Tags})
+	}
+	for _, path := range result.CriticalPaths {
+		doc.CriticalPaths = append(doc.CriticalPaths, nonNil(path))
+	}
+	for _, ts := range result.TaskSchedules {
+		doc.Tasks = append(doc.Tasks, TaskDocument{
+			ID:           ts.TaskID,
+			WorkerID:     ts.WorkerID,
+			Start:        ts.EarliestStart,
+			Finish:       ts.EarliestFinish,
+			Duration:     ts.EarliestFinish - ts.EarliestStart,
+			LatestStart:  ts.LatestStart,
+			LatestFinish: ts.LatestFinish,
+			TotalFloat:   ts.TotalFloat,
+			FreeFloat:    ts.FreeFloat,
+			Critical:     critical[ts.TaskID],
+			Status:       string(ts.Status),
+			Attempts:     ts.Attempts,
+		})
+	}
+	for _, wt := range result.WorkerTimelines {
+		ids := make([]string, 0, len(wt.Tasks))
+		for _, ts := range wt.Tasks {
+			ids = append(ids, ts.TaskID)
+		}
+		doc.WorkerTimelines = append(doc.WorkerTimelines, TimelineDocument{
+			WorkerID: wt.WorkerID,
+			BusyTime: wt.BusyTime(),
+			Tasks:    ids,
+		})
+	}
+	return doc
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// JSONPrinter writes the ScheduleResult as an indented ResultDocument.
+type JSONPrinter struct {
+	writer io.Writer
+}
+
+// NewJSONPrinter creates a JSON printer that writes to stdout.
+func NewJSONPrinter() *JSONPrinter {
+	return &JSONPrinter{writer: os.Stdout}
+}
+
+// NewJSONPrinterWithWriter creates a JSON printer that writes to the given writer.
+func NewJSONPrinterWithWriter(w io.Writer) *JSONPrinter {
+	return &JSONPrinter{writer: w}
+}
+
+// Print encodes the result followed by a newline.
+func (p *JSONPrinter) Print(result *model.ScheduleResult) {
+	enc := json.NewEncoder(p.writer)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(NewResultDocument(result))
+}
+
+// timeUnit returns the time unit of result as written in the exports:
+// "unit" for abstract planning units, or the result's own unit, "ms" for
+// the measured times of an executed job.
+func timeUnit(result *model.ScheduleResult) string {
+	if result.TimeUnit == "" {
+		return "unit"
+	}
+	return result.TimeUnit
+}
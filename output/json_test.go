@@ -0,0 +1,82 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+func TestJSONPrinter(t *testing.T) {
+	result := ganttResult(2)
+	result.ExecutionOrder = []string{"A", "B", "C", "D", "E", "F"}
+	result.CriticalTasks = []string{"A", "D", "F"}
+
+	var buf bytes.Buffer
+	NewJSONPrinterWithWriter(&buf).Print(result)
+	var doc ResultDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.SchemaVersion != SchemaVersion || doc.Job != "J" || doc.Workers != 2 || doc.MinCompletionTime != 11 || doc.TimeUnit != "unit" {
+		t.Errorf("header %+v", doc)
+	}
+	if len(doc.Tasks) != 6 {
+		t.Fatalf("%d task(s), want 6", len(doc.Tasks))
+	}
+	if c := doc.Tasks[1]; c.ID != "D" || c.WorkerID != 1 || c.Start != 3 || c.Finish != 8 || c.Duration != 5 || !c.Critical {
+		t.Errorf("task D: %+v", c)
+	}
+	if b := doc.Tasks[3]; b.ID != "B" || b.TotalFloat != 4 || b.Critical {
+		t.Errorf("task B: %+v", b)
+	}
+	if len(doc.WorkerTimelines) != 2 || doc.WorkerTimelines[1].BusyTime != 8 ||
+		strings.Join(doc.WorkerTimelines[1].Tasks, ",") != "B,C,E" {
+		t.Errorf("timelines %+v", doc.WorkerTimelines)
+	}
+}
+
+// Every export names its time unit, planned or measured.
+func TestTimeUnit(t *testing.T) {
+	for _, tt := range []struct{ unit, want string }{{"", "unit"}, {"ms", "ms"}} {
+		if got := NewResultDocument(&model.ScheduleResult{TimeUnit: tt.unit}).TimeUnit; got != tt.want {
+			t.Errorf("time unit %q exported as %q, want %q", tt.unit, got, tt.want)
+		}
+	}
+}
+
+// Missing lists encode as [] so that consumers never see null.
+func TestJSONPrinterEmptyLists(t *testing.T) {
+	var buf bytes.Buffer
+	NewJSONPrinterWithWriter(&buf).Print(&model.ScheduleResult{JobName: "J"})
+	for _, key := range []string{"execution_order", "critical_path", "critical_paths", "critical_tasks", "tasks", "worker_timelines"} {
+		if !strings.Contains(buf.String(), `"`+key+`": []`) {
+			t.Errorf("%s is not an empty list:\n%s", key, buf.String())
+		}
+	}
+}
+
+func TestCSVPrinter(t *testing.T) {
+	result := ganttResult(2)
+	result.CriticalTasks = []string{"A", "D", "F"}
+
+	var buf bytes.Buffer
+	NewCSVPrinterWithWriter(&buf).Print(result)
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 7 {
+		t.Fatalf("%d row(s), want a header and 6 tasks", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Errorf("header %v, want %v", rows[0], csvHeader)
+	}
+	want := "2,J,2,11,unit,2,D,1,3,8,5,0,0,0,0,true,,"
+	if got := strings.Join(rows[2], ","); got != want {
+		t.Errorf("row 2 = %s, want %s", got, want)
+	}
+}
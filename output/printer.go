@@ -2,9 +2,11 @@
 package output
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"wingie_case/model"
@@ -15,6 +17,12 @@ type Printer interface {
 	Print(result *model.ScheduleResult)
 }
 
+// ErrorPrinter is implemented by printers that can render a failed run,
+// e.g. the list of validation problems and dependency cycles.
+type ErrorPrinter interface {
+	PrintError(err error)
+}
+
 // ConsolePrinter writes a human-readable schedule to an io.Writer.
 type ConsolePrinter struct {
 	writer io.Writer
@@ -33,34 +41,232 @@ func NewConsolePrinterWithWriter(w io.Writer) *ConsolePrinter {
 // Print renders the schedule: summary, task table, and execution order.
 func (p *ConsolePrinter) Print(result *model.ScheduleResult) {
 	w := p.writer
-	line := strings.Repeat("=", 60)
-	dash := strings.Repeat("-", 60)
+	line := strings.Repeat("=", 83)
+	dash := strings.Repeat("-", 83)
 
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, line)
 	fmt.Fprintf(w, "  Job: %s\n", result.JobName)
 	fmt.Fprintf(w, "  Workers: %d\n", result.Workers)
+	if len(result.Pool) > 0 {
+		fmt.Fprintf(w, "  Worker pool: %s\n", describePool(result.Pool))
+	}
+	if len(result.Resources) > 0 {
+		fmt.Fprintf(w, "  Resources: %s\n", describeResources(result.Resources))
+	}
+	if result.PriorityRule != "" {
+		fmt.Fprintf(w, "  Priority rule: %s\n", result.PriorityRule)
+	}
 	fmt.Fprintln(w, line)
 
-	fmt.Fprintf(w, "  Minimum completion time : %d unit(s)\n", result.MinCompletionTime)
-	if len(result.CriticalPath) > 0 {
+	if result.TimeUnit == "" {
+		fmt.Fprintf(w, "  Minimum completion time : %d %s\n", result.MinCompletionTime, result.Unit())
+	} else {
+		fmt.Fprintf(w, "  Measured completion time: %d %s\n", result.MinCompletionTime, result.Unit())
+	}
+	if gap := result.OptimalityGap(); gap >= 0 {
+		b := result.Bounds
+		fmt.Fprintf(w, "  Lower bound             : %d (%s)\n", result.LowerBound, describeBounds(b))
+		if result.Optimal {
+			fmt.Fprintf(w, "  Optimality              : proven optimal\n")
+		} else {
+			fmt.Fprintf(w, "  Optimality              : gap %d %s (%.1f%%)\n", gap, result.Unit(), result.OptimalityGapPercent())
+		}
+		fmt.Fprintf(w, "  Limited by              : %s\n", limitingFactor(result))
+	}
+	switch {
+	case result.CriticalPathsTruncated:
+		fmt.Fprintf(w, "  Critical paths          : more than %d, showing first %d\n",
+			len(result.CriticalPaths), len(result.CriticalPaths))
+		printPaths(w, result.CriticalPaths)
+	case len(result.CriticalPaths) > 1:
+		fmt.Fprintf(w, "  Critical paths          : %d\n", len(result.CriticalPaths))
+		printPaths(w, result.CriticalPaths)
+	case len(result.CriticalPath) > 0:
 		fmt.Fprintf(w, "  Critical path           : %s\n", strings.Join(result.CriticalPath, " -> "))
 	}
+	if len(result.CriticalTasks) > 0 {
+		fmt.Fprintf(w, "  Critical tasks          : %s\n", strings.Join(result.CriticalTasks, ", "))
+	}
 
 	fmt.Fprintln(w, dash)
 	fmt.Fprintln(w, "  Execution Plan:")
 	fmt.Fprintln(w, dash)
-	fmt.Fprintf(w, "  %-8s %12s %12s %12s\n", "Task", "Start", "Finish", "Duration")
+	fmt.Fprintf(w, "  %-8s %6s %8s %8s %8s %8s %8s %8s %8s\n",
+		"Task", "Worker", "Start", "Finish", "Duration", "LS", "LF", "TF", "FF")
 	fmt.Fprintln(w, dash)
 
 	for _, ts := range result.TaskSchedules {
 		dur := ts.EarliestFinish - ts.EarliestStart
-		fmt.Fprintf(w, "  %-8s %12d %12d %12d\n",
-			ts.TaskID, ts.EarliestStart, ts.EarliestFinish, dur)
+		fmt.Fprintf(w, "  %-8s %6s %8d %8d %8d %8d %8d %8d %8d\n",
+			ts.TaskID, workerLabel(ts.WorkerID), ts.EarliestStart, ts.EarliestFinish, dur,
+			ts.LatestStart, ts.LatestFinish, ts.TotalFloat, ts.FreeFloat)
 	}
 
 	fmt.Fprintln(w, dash)
+	fmt.Fprintln(w, "  LS/LF = latest start/finish, TF = total float, FF = free float")
 	fmt.Fprintf(w, "  Execution order: [%s]\n", strings.Join(result.ExecutionOrder, ", "))
+
+	if byStatus := tasksByStatus(result); len(byStatus) > 0 {
+		fmt.Fprintln(w, dash)
+		fmt.Fprintln(w, "  Task Status:")
+		fmt.Fprintln(w, dash)
+		for _, status := range model.TaskStatuses {
+			if len(byStatus[status]) > 0 {
+				fmt.Fprintf(w, "  %-9s : %s\n", status, strings.Join(byStatus[status], ", "))
+			}
+		}
+	}
+
+	if len(result.WorkerTimelines) > 0 {
+		fmt.Fprintln(w, dash)
+		fmt.Fprintln(w, "  Worker Timelines:")
+		fmt.Fprintln(w, dash)
+		for _, wt := range result.WorkerTimelines {
+			steps := make([]string, 0, len(wt.Tasks))
+			for _, ts := range wt.Tasks {
+				steps = append(steps, fmt.Sprintf("%s(%d-%d)", ts.TaskID, ts.EarliestStart, ts.EarliestFinish))
+			}
+			if len(steps) == 0 {
+				steps = append(steps, "(idle)")
+			}
+			fmt.Fprintf(w, "  %-6s busy %3d/%d : %s\n", workerLabel(wt.WorkerID),
+				wt.BusyTime(), result.MinCompletionTime, strings.Join(steps, " -> "))
+		}
+	}
 	fmt.Fprintln(w, line)
 }
 
+// PrintError renders validation problems one per line; each dependency
+// cycle is spelled out together with the tasks it blocks. Errors that join
+// several problems (Unwrap() []error, as validator.ValidationErrors does)
+// are split; other errors are printed as a single line.
+func (p *ConsolePrinter) PrintError(err error) {
+	w := p.writer
+	line := strings.Repeat("=", 83)
+	dash := strings.Repeat("-", 83)
+
+	problems := []error{err}
+	var multi interface{ Unwrap() []error }
+	if errors.As(err, &multi) {
+		problems = multi.Unwrap()
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "  Job is invalid: %d problem(s)\n", len(problems))
+	fmt.Fprintln(w, line)
+	for _, problem := range problems {
+		var cycleErr *model.CycleError
+		if !errors.As(problem, &cycleErr) {
+			fmt.Fprintf(w, "  - %v\n", problem)
+			continue
+		}
+		fmt.Fprintf(w, "  - %s\n", cycleErr.Message)
+		for i, cycle := range cycleErr.Cycles {
+			fmt.Fprintf(w, "      cycle %d: %s\n", i+1, strings.Join(cycle, " -> "))
+		}
+		if len(cycleErr.Unvisited) > 0 {
+			fmt.Fprintf(w, "      blocked tasks: %s\n", strings.Join(cycleErr.Unvisited, ", "))
+		}
+	}
+	fmt.Fprintln(w, dash)
+}
+
+// printPaths lists critical paths one per line, numbered from 1.
+func printPaths(w io.Writer, paths [][]string) {
+	for i, path := range paths {
+		fmt.Fprintf(w, "    %2d) %s\n", i+1, strings.Join(path, " -> "))
+	}
+}
+
+// tasksByStatus groups the tasks of an executed job by outcome, in start
+// order; retried tasks are shown as "C (3 attempts)". It returns nil for a
+// plan.
+func tasksByStatus(result *model.ScheduleResult) map[model.TaskStatus][]string {
+	var byStatus map[model.TaskStatus][]string
+	for _, ts := range result.TaskSchedules {
+		if ts.Status == "" {
+			continue
+		}
+		if byStatus == nil {
+			byStatus = make(map[model.TaskStatus][]string)
+		}
+		label := ts.TaskID
+		if ts.Attempts > 1 {
+			label = fmt.Sprintf("%s (%d attempts)", ts.TaskID, ts.Attempts)
+		}
+		byStatus[ts.Status] = append(byStatus[ts.Status], label)
+	}
+	return byStatus
+}
+
+// describePool lists the workers as "W1 fast x2 [gpu], W2 slow x0.5".
+func describePool(pool []model.Worker) string {
+	parts := make([]string, 0, len(pool))
+	for _, wk := range pool {
+		label := workerLabel(wk.ID)
+		if wk.Name != label {
+			label += " " + wk.Name
+		}
+		label = fmt.Sprintf("%s x%g", label, wk.Speed)
+		if len(wk.Tags) > 0 {
+			label += " [" + strings.Join(wk.Tags, ", ") + "]"
+		}
+		parts = append(parts, label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeResources lists capacities by name as "cpu 4, license 1".
+func describeResources(capacities map[string]int) string {
+	names := make([]string, 0, len(capacities))
+	for name := range capacities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %d", name, capacities[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// workerLabel formats a worker ID as "W1", or "-" when unassigned.
+func workerLabel(id int) string {
+	if id <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("W%d", id)
+}
+
+// describeBounds lists the individual bounds, e.g. "critical path 11, work
+// 10, energy 11"; the tag and resource bounds only when they apply.
+func describeBounds(b model.LowerBounds) string {
+	parts := []string{fmt.Sprintf("critical path %d", b.CriticalPath), fmt.Sprintf("work %d", b.Work)}
+	if b.Tag > 0 {
+		parts = append(parts, fmt.Sprintf("tags %d", b.Tag))
+	}
+	if b.Resource > 0 {
+		parts = append(parts, fmt.Sprintf("resources %d", b.Resource))
+	}
+	parts = append(parts, fmt.Sprintf("energy %d", b.Energy))
+	return strings.Join(parts, ", ")
+}
+
+// limitingFactor explains what keeps the completion time from going lower:
+// the first bound it reaches, or the schedule itself when it reaches none.
+func limitingFactor(result *model.ScheduleResult) string {
+	b, completion := result.Bounds, result.MinCompletionTime
+	switch {
+	case completion == b.CriticalPath:
+		return "dependencies (critical path); more workers will not help"
+	case completion == b.Resource:
+		return "resource capacity; only more of the scarcest resource can help"
+	case completion == b.Tag:
+		return "workers with a required tag; only more such workers can help"
+	case completion == b.Work || completion == b.Energy || result.Optimal:
+		return "worker capacity; only more workers can help"
+	}
+	return "possibly the schedule itself; try another --priority or --optimal"
+}
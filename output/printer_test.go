@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+func TestLimitingFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  model.LowerBounds
+		optimal bool
+		want    string
+	}{
+		{name: "critical path", bounds: model.LowerBounds{CriticalPath: 9, Work: 5}, optimal: true, want: "dependencies"},
+		{name: "resource", bounds: model.LowerBounds{CriticalPath: 3, Work: 3, Resource: 9}, optimal: true, want: "resource capacity"},
+		{name: "tag", bounds: model.LowerBounds{CriticalPath: 2, Work: 2, Tag: 9}, optimal: true, want: "required tag"},
+		{name: "work", bounds: model.LowerBounds{CriticalPath: 3, Work: 9}, optimal: true, want: "worker capacity"},
+		{name: "energy", bounds: model.LowerBounds{CriticalPath: 3, Work: 5, Energy: 9}, optimal: true, want: "worker capacity"},
+		{name: "gap", bounds: model.LowerBounds{CriticalPath: 3, Work: 5}, want: "the schedule itself"},
+	}
+	for _, tt := range tests {
+		result := &model.ScheduleResult{MinCompletionTime: 9, Bounds: tt.bounds, LowerBound: tt.bounds.Best(), Optimal: tt.optimal}
+		if got := limitingFactor(result); !strings.Contains(got, tt.want) {
+			t.Errorf("%s: limitingFactor = %q, want it to mention %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeBounds(t *testing.T) {
+	tests := []struct {
+		bounds model.LowerBounds
+		want   string
+	}{
+		{bounds: model.LowerBounds{CriticalPath: 11, Work: 10, Energy: 11}, want: "critical path 11, work 10, energy 11"},
+		{bounds: model.LowerBounds{CriticalPath: 3, Work: 3, Tag: 6, Resource: 9, Energy: 3}, want: "critical path 3, work 3, tags 6, resources 9, energy 3"},
+	}
+	for _, tt := range tests {
+		if got := describeBounds(tt.bounds); got != tt.want {
+			t.Errorf("describeBounds(%+v) = %q, want %q", tt.bounds, got, tt.want)
+		}
+	}
+}
+
+func TestCriticalPathsTruncated(t *testing.T) {
+	result := &model.ScheduleResult{
+		JobName:                "J",
+		Workers:                2,
+		MinCompletionTime:      2,
+		CriticalPaths:          [][]string{{"a", "c"}, {"b", "c"}},
+		CriticalPath:           []string{"a", "c"},
+		CriticalPathsTruncated: true,
+	}
+	var console bytes.Buffer
+	NewConsolePrinterWithWriter(&console).Print(result)
+	if !strings.Contains(console.String(), "more than 2, showing first 2") {
+		t.Errorf("console output does not mention the truncation:\n%s", console.String())
+	}
+
+	var doc bytes.Buffer
+	NewJSONPrinterWithWriter(&doc).Print(result)
+	var decoded ResultDocument
+	if err := json.Unmarshal(doc.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.CriticalPathsTruncated || len(decoded.CriticalPaths) != 2 {
+		t.Errorf("JSON has truncated %v with %d path(s), want true with 2",
+			decoded.CriticalPathsTruncated, len(decoded.CriticalPaths))
+	}
+}
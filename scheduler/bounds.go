@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"math"
+	"sort"
+
+	"wingie_case/model"
+)
+
+// computeLowerBounds returns bounds that no schedule of job on the given
+// worker pool and resource capacities can beat:
+//
+//   - CriticalPath: the longest dependency chain.
+//   - Work: total duration divided by the capacity of the pool (the number
+//     of workers when they are identical), rounded up.
+//   - Tag: for each tag tasks require, their work divided by the capacity of
+//     the workers having it.
+//   - Resource: for each resource, the demand on it (units times duration on
+//     the fastest worker) divided by its capacity.
+//   - Energy: for every pair of thresholds (h, q), the tasks that cannot start
+//     before h (head >= h) and are followed by at least q units of dependent
+//     work (tail >= q) need h + ceil(sum of their durations / capacity) + q.
+//     With h = q = 0 this is the work bound; it is stronger when long chains
+//     force part of the work to happen late or early.
+//
+// Heads and tails come from CPM with every task on the fastest worker: a
+// task's head is its earliest start, its tail the longest chain of
+// successors after it finishes. Typed links make them depend on the actual
+// durations: a lead can be longer on a slow worker than on a fast one, so
+// with workers of mixed speeds the critical path and energy bounds are
+// skipped.
+func computeLowerBounds(job *model.Job, pool []model.Worker, capacities map[string]int) model.LowerBounds {
+	fastest := scaledJob(job, fastestSpeed(pool))
+	lengths := remainingPathLengths(fastest)
+	heads := earliestStarts(fastest)
+
+	type window struct{ head, dur, tail int }
+	tasks := make([]window, 0, job.TaskCount())
+	_, uniform := model.UniformSpeed(pool)
+	chains := uniform || !job.HasTypedLinks()
+	var bounds model.LowerBounds
+	total := 0
+	for id, task := range job.Tasks {
+		tail := lengths[id] - fastest.Tasks[id].Duration
+		tasks = append(tasks, window{head: heads[id], dur: task.Duration, tail: tail})
+		if chains {
+			bounds.CriticalPath = max(bounds.CriticalPath, heads[id]+lengths[id])
+		}
+		total += task.Duration
+	}
+	if len(pool) == 0 || len(tasks) == 0 {
+		return bounds
+	}
+	speed := capacity(pool)
+	bounds.Work = ceilWork(total, speed)
+	bounds.Tag = tagWork(job, pool)
+	bounds.Resource = resourceWork(fastest, capacities)
+	if !chains {
+		return bounds
+	}
+
+	// Tasks sorted by decreasing head: sweeping h downwards adds tasks one
+	// at a time, so each tail threshold costs O(n).
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].head > tasks[j].head })
+	tails := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		tails[t.tail] = true
+	}
+	for q := range tails {
+		work := 0
+		for i, t := range tasks {
+			if t.tail >= q {
+				work += t.dur
+			}
+			// Evaluate once all tasks with this head have been added.
+			if work > 0 && (i+1 == len(tasks) || tasks[i+1].head != t.head) {
+				bounds.Energy = max(bounds.Energy, t.head+ceilWork(work, speed)+q)
+			}
+		}
+	}
+	return bounds
+}
+
+// tagWork returns the largest work bound over the tags tasks require: the
+// tasks requiring a tag can only share the workers that have it.
+func tagWork(job *model.Job, pool []model.Worker) int {
+	work := make(map[string]int)
+	for _, task := range job.Tasks {
+		for _, tag := range task.Requires {
+			work[tag] += task.Duration
+		}
+	}
+	best := 0
+	for tag, w := range work {
+		var eligible []model.Worker
+		for _, wk := range pool {
+			if wk.HasTag(tag) {
+				eligible = append(eligible, wk)
+			}
+		}
+		if len(eligible) > 0 {
+			best = max(best, ceilWork(w, capacity(eligible)))
+		}
+	}
+	return best
+}
+
+// resourceWork returns the largest number of time units any resource must
+// be in use, given its capacity.
+func resourceWork(job *model.Job, capacities map[string]int) int {
+	demand := make(map[string]int, len(capacities))
+	for _, task := range job.Tasks {
+		for name, units := range task.Resources {
+			demand[name] += units * task.Duration
+		}
+	}
+	best := 0
+	for name, d := range demand {
+		if capacities[name] > 0 {
+			best = max(best, ceilWork(d, float64(capacities[name])))
+		}
+	}
+	return best
+}
+
+// ceilWork returns how long, rounded up, a pool of the given capacity needs
+// for work units of reference work.
+func ceilWork(work int, capacity float64) int {
+	// The epsilon keeps exact quotients from rounding up.
+	return int(math.Ceil(float64(work)/capacity - 1e-9))
+}
+
+// earliestStarts returns each task's CPM earliest start (its head).
+func earliestStarts(job *model.Job) map[string]int {
+	heads := make(map[string]int, job.TaskCount())
+	var visit func(id string) int
+	visit = func(id string) int {
+		if v, ok := heads[id]; ok {
+			return v
+		}
+		task := job.Tasks[id]
+		start := 0
+		for _, depID := range task.Dependencies {
+			dur := job.Tasks[depID].Duration
+			start = max(start, visit(depID)+dur+task.Link(depID).Gap(dur, task.Duration))
+		}
+		heads[id] = start
+		return start
+	}
+	for id := range job.Tasks {
+		visit(id)
+	}
+	return heads
+}
+
+// applyLowerBounds records the bounds on result and whether its completion
+// time reaches the best of them.
+func applyLowerBounds(result *model.ScheduleResult, bounds model.LowerBounds) {
+	result.Bounds = bounds
+	result.LowerBound = bounds.Best()
+	result.Optimal = result.MinCompletionTime == result.LowerBound
+}
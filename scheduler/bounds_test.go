@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"wingie_case/model"
+)
+
+func TestComputeLowerBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		specs      []spec
+		requires   map[string][]string
+		resources  map[string]map[string]int
+		capacities map[string]int
+		pool       []model.Worker
+		want       model.LowerBounds
+	}{
+		{
+			name: "work",
+			specs: []spec{
+				{id: "a", dur: 3}, {id: "b", dur: 3},
+				{id: "c", dur: 2}, {id: "d", dur: 2}, {id: "e", dur: 2},
+			},
+			pool: identicalWorkers(2),
+			want: model.LowerBounds{CriticalPath: 3, Work: 6, Energy: 6},
+		},
+		{
+			name: "critical path",
+			specs: []spec{
+				{id: "a", dur: 2},
+				{id: "b", dur: 3, deps: []string{"a"}},
+				{id: "c", dur: 1},
+			},
+			pool: identicalWorkers(3),
+			want: model.LowerBounds{CriticalPath: 5, Work: 2, Energy: 4},
+		},
+		{
+			// b, c and d cannot start before 4 and need 3 units on two workers.
+			name: "energy",
+			specs: []spec{
+				{id: "a", dur: 4},
+				{id: "b", dur: 2, deps: []string{"a"}},
+				{id: "c", dur: 2, deps: []string{"a"}},
+				{id: "d", dur: 2, deps: []string{"a"}},
+			},
+			pool: identicalWorkers(2),
+			want: model.LowerBounds{CriticalPath: 6, Work: 5, Energy: 7},
+		},
+		{
+			// Only one of the four workers can run the tasks.
+			name:     "tag",
+			specs:    []spec{{id: "a", dur: 2}, {id: "b", dur: 2}, {id: "c", dur: 2}},
+			requires: map[string][]string{"a": {"gpu"}, "b": {"gpu"}, "c": {"gpu"}},
+			pool: []model.Worker{
+				{ID: 1, Speed: 1, Tags: []string{"gpu"}},
+				{ID: 2, Speed: 1}, {ID: 3, Speed: 1}, {ID: 4, Speed: 1},
+			},
+			want: model.LowerBounds{CriticalPath: 2, Work: 2, Tag: 6, Energy: 2},
+		},
+		{
+			// The single license makes the tasks run one after the other.
+			name:  "resource",
+			specs: []spec{{id: "a", dur: 3}, {id: "b", dur: 3}, {id: "c", dur: 3}},
+			resources: map[string]map[string]int{
+				"a": {"license": 1}, "b": {"license": 1}, "c": {"license": 1},
+			},
+			capacities: map[string]int{"license": 1},
+			pool:       identicalWorkers(4),
+			want:       model.LowerBounds{CriticalPath: 3, Work: 3, Resource: 9, Energy: 3},
+		},
+		{
+			// Speeds 1 and 2 do three units of work per time unit, and a task
+			// of 3 takes 2 on the fast worker.
+			name:  "mixed speeds",
+			specs: []spec{{id: "a", dur: 3}, {id: "b", dur: 3}},
+			pool:  []model.Worker{{ID: 1, Speed: 1}, {ID: 2, Speed: 2}},
+			want:  model.LowerBounds{CriticalPath: 2, Work: 2, Energy: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := buildJob(t, tt.specs...)
+			for id, tags := range tt.requires {
+				job.Tasks[id].Requires = tags
+			}
+			for id, amounts := range tt.resources {
+				job.Tasks[id].Resources = amounts
+			}
+			got := computeLowerBounds(job, tt.pool, tt.capacities)
+			if got != tt.want {
+				t.Errorf("bounds %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// On the slow worker y takes 2, so FF+1 lets it start with x and z can start
+// at 2, finishing at 3. With every task on the fast worker y would start at
+// 1 and the chain would take 4, which is no bound.
+func TestLowerBoundMixedSpeedsTypedLinks(t *testing.T) {
+	job := buildJob(t,
+		spec{id: "x", dur: 2},
+		spec{id: "y", dur: 2, deps: []string{"x"}, links: map[string]string{"x": "FF+1"}},
+		spec{id: "z", dur: 2, deps: []string{"y"}, links: map[string]string{"y": "SS+2"}},
+	)
+	job.Pool = []model.Worker{{ID: 1, Name: "W1", Speed: 2}, {ID: 2, Name: "W2", Speed: 1}}
+	result, err := NewWorkerScheduler().Schedule(job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, job, result)
+	if result.MinCompletionTime != 3 {
+		t.Errorf("completion time %d, want 3", result.MinCompletionTime)
+	}
+	if result.LowerBound > result.MinCompletionTime {
+		t.Errorf("lower bound %d (%+v) exceeds completion time %d", result.LowerBound, result.Bounds, result.MinCompletionTime)
+	}
+}
+
+// No bound may exceed the optimum.
+func TestLowerBoundsNeverExceedOptimum(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 40; i++ {
+		job := randomJob(rng, 4+rng.Intn(4), 0.3)
+		workers := 1 + rng.Intn(3)
+		optimum := exhaustiveMakespan(job, workers)
+		bounds := computeLowerBounds(job, identicalWorkers(workers), nil)
+		for name, bound := range map[string]int{
+			"critical path": bounds.CriticalPath,
+			"work":          bounds.Work,
+			"energy":        bounds.Energy,
+		} {
+			if bound > optimum {
+				t.Errorf("job%d on %d worker(s): %s bound %d exceeds the optimum %d", i, workers, name, bound, optimum)
+			}
+		}
+	}
+}
+
+// Whatever the pool, tags, resources and links, the lower bound a scheduler reports is at most its
+// completion time, and a schedule that reaches it is marked optimal.
+func TestLowerBoundAtMostMakespan(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 60; i++ {
+		job := randomJob(rng, 3+rng.Intn(8), 0.3)
+		workers := 1 + rng.Intn(3)
+		notations := []string{"FS", "SS-1", "FF+1", "SF-2", "FS-1", "SS+2"}
+		if rng.Intn(2) == 0 {
+			for k := 1; k <= workers; k++ {
+				job.Pool = append(job.Pool, model.Worker{ID: k, Name: fmt.Sprintf("W%d", k), Speed: float64(1 + rng.Intn(3))})
+			}
+			job.Pool[0].Tags = []string{"gpu"}
+		}
+		for k := 0; k < job.TaskCount(); k++ {
+			task := job.Tasks[fmt.Sprintf("t%d", k)] // in order, so the job only depends on the seed
+			switch rng.Intn(4) {
+			case 0:
+				if len(job.Pool) > 0 {
+					task.Requires = []string{"gpu"}
+				}
+			case 1:
+				task.Resources = map[string]int{"license": 1 + rng.Intn(2)}
+				job.Resources = map[string]int{"license": 2}
+			}
+			for _, dep := range task.Dependencies {
+				if rng.Intn(3) == 0 {
+					link, _ := model.ParseLink(notations[rng.Intn(len(notations))])
+					if task.Links == nil {
+						task.Links = map[string]model.Link{}
+					}
+					task.Links[dep] = link
+				}
+			}
+		}
+		for _, s := range []struct {
+			name  string
+			sched Scheduler
+		}{
+			{"worker", NewWorkerScheduler()},
+			{"optimal", NewOptimalScheduler()},
+			{"resource", NewResourceScheduler()},
+		} {
+			result, err := s.sched.Schedule(job, workers)
+			if err != nil {
+				t.Fatalf("job%d, %s: %v", i, s.name, err)
+			}
+			checkLinks(t, job, result)
+			if result.LowerBound > result.MinCompletionTime {
+				t.Errorf("job%d, %s: lower bound %d (%+v) exceeds completion time %d",
+					i, s.name, result.LowerBound, result.Bounds, result.MinCompletionTime)
+			}
+			if result.Optimal != (result.LowerBound == result.MinCompletionTime) {
+				t.Errorf("job%d, %s: optimal %v with lower bound %d and completion time %d",
+					i, s.name, result.Optimal, result.LowerBound, result.MinCompletionTime)
+			}
+		}
+	}
+}
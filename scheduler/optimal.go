@@ -0,0 +1,378 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"wingie_case/model"
+)
+
+// Defaults for OptimalScheduler.
+const (
+	DefaultOptimalMaxTasks = 20
+	DefaultOptimalTimeout  = 5 * time.Second
+)
+
+// OptimalScheduler computes a minimum-completion-time schedule for jobs with
+// limited workers using branch and bound.
+//
+// The search enumerates task lists for the serial schedule generation scheme
+// (each task starts at the earliest time its dependencies and the free
+// workers allow). Every such schedule is "active", and the set of active
+// schedules always contains an optimal one, so an exhausted search proves
+// optimality. Lists are only extended in non-decreasing start order, which
+// removes permutations that generate the same schedule.
+//
+// Jobs with more than maxTasks tasks, jobs whose worker pool mixes speeds
+// and jobs whose tasks require worker tags or resources or have typed links
+// are scheduled with the list scheduler (longest-remaining-path rule); pools of equally fast workers are
+// searched on scaled durations. When the timeout expires the best schedule
+// found so far is returned. In both cases Optimal is only set when the
+// result reaches a lower bound, and LowerBound shows how far from optimal the
+// result can be.
+type OptimalScheduler struct {
+	maxTasks int
+	timeout  time.Duration
+}
+
+// NewOptimalScheduler creates an OptimalScheduler with the default limits.
+func NewOptimalScheduler() *OptimalScheduler {
+	return NewOptimalSchedulerWithLimits(DefaultOptimalMaxTasks, DefaultOptimalTimeout)
+}
+
+// NewOptimalSchedulerWithLimits creates an OptimalScheduler that searches
+// jobs of up to maxTasks tasks for at most timeout. Non-positive values
+// select the defaults.
+func NewOptimalSchedulerWithLimits(maxTasks int, timeout time.Duration) *OptimalScheduler {
+	if maxTasks <= 0 {
+		maxTasks = DefaultOptimalMaxTasks
+	}
+	if timeout <= 0 {
+		timeout = DefaultOptimalTimeout
+	}
+	return &OptimalScheduler{maxTasks: maxTasks, timeout: timeout}
+}
+
+// Schedule returns a schedule with the minimum completion time, or the best
+// one found within the configured limits.
+func (o *OptimalScheduler) Schedule(job *model.Job, workers int) (*model.ScheduleResult, error) {
+	return o.ScheduleContext(context.Background(), job, workers)
+}
+
+// ScheduleContext is Schedule, giving up once ctx is done. The search then
+// stops as on a timeout, but the context's error is returned.
+func (o *OptimalScheduler) ScheduleContext(ctx context.Context, job *model.Job, workers int) (*model.ScheduleResult, error) {
+	if workers <= 0 {
+		return nil, fmt.Errorf("workers must be positive, got %d", workers)
+	}
+	// The search knows nothing of resources.
+	if job.HasResources() {
+		return NewResourceSchedulerWithRule(builtinRules[RuleLongestRemainingPath]).ScheduleContext(ctx, job, workers)
+	}
+	pool, err := WorkerPool(job, workers)
+	if err != nil {
+		return nil, err
+	}
+	// The search treats workers as interchangeable and dependencies as
+	// finish-to-start.
+	speed, uniform := model.UniformSpeed(pool)
+	interchangeable := uniform && !job.HasRequirements()
+	searchable := interchangeable && !job.HasTypedLinks()
+
+	// With at least one worker per task CPM is already optimal.
+	if interchangeable && workers >= job.TaskCount() {
+		return NewWorkerScheduler().ScheduleContext(ctx, job, workers)
+	}
+
+	// The list schedule is the starting upper bound, and the answer when the
+	// job is too large to search or already matches a lower bound.
+	heuristic, err := NewWorkerSchedulerWithRule(builtinRules[RuleLongestRemainingPath]).ScheduleContext(ctx, job, workers)
+	if err != nil {
+		return nil, err
+	}
+	if heuristic.Optimal || !searchable || job.TaskCount() > o.maxTasks {
+		return heuristic, nil
+	}
+	declared := job.Pool
+	job = scaledJob(job, speed)
+
+	search := newBranchAndBound(job, workers, heuristic.LowerBound)
+
+	starts := make(map[string]int, len(heuristic.TaskSchedules))
+	for _, ts := range heuristic.TaskSchedules {
+		starts[ts.TaskID] = ts.EarliestStart
+	}
+	search.seed(starts, heuristic.MinCompletionTime)
+	proven := search.run(ctx, time.Now().Add(o.timeout))
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := NewWorkerScheduler().resultFromStarts(job, workers, search.bestStarts())
+	if err != nil {
+		return nil, err
+	}
+	setPool(result, declared)
+	if proven {
+		result.LowerBound = result.MinCompletionTime
+		result.Optimal = true
+	}
+	return result, nil
+}
+
+// branchAndBound holds the search state. Tasks are indexed by position in
+// ids (sorted by ID) so that the search is deterministic.
+type branchAndBound struct {
+	ids     []string
+	workers int
+	dur     []int
+	preds   [][]int
+	topo    []int // indices in topological order
+	tail    []int // duration plus the longest chain of successors
+	branch  []int // indices in branching order (longest tail first)
+
+	start     []int // -1 while unscheduled
+	scheduled []int // scheduled indices, in list order
+	remaining int   // total duration of unscheduled tasks
+
+	best         []int
+	bestMakespan int
+	lowerBound   int // search stops early once the incumbent reaches it
+
+	nodes    int
+	ctx      context.Context
+	deadline time.Time
+	timedOut bool
+}
+
+func newBranchAndBound(job *model.Job, workers, lowerBound int) *branchAndBound {
+	ids := make([]string, 0, job.TaskCount())
+	for id := range job.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	n := len(ids)
+	b := &branchAndBound{
+		ids:        ids,
+		workers:    workers,
+		dur:        make([]int, n),
+		preds:      make([][]int, n),
+		start:      make([]int, n),
+		lowerBound: lowerBound,
+	}
+	lengths := remainingPathLengths(job)
+	for i, id := range ids {
+		task := job.Tasks[id]
+		b.dur[i] = task.Duration
+		b.remaining += task.Duration
+		b.start[i] = -1
+		b.tail = append(b.tail, lengths[id])
+		for _, depID := range task.Dependencies {
+			b.preds[i] = append(b.preds[i], index[depID])
+		}
+	}
+
+	// Topological order by increasing head (longest path to the task) keeps
+	// predecessors first because durations are positive.
+	heads := make([]int, n)
+	b.topo = make([]int, n)
+	for i := range b.topo {
+		b.topo[i] = i
+	}
+	var head func(i int) int
+	done := make([]bool, n)
+	head = func(i int) int {
+		if !done[i] {
+			for _, p := range b.preds[i] {
+				heads[i] = max(heads[i], head(p)+b.dur[p])
+			}
+			done[i] = true
+		}
+		return heads[i]
+	}
+	for i := range ids {
+		head(i)
+	}
+	sort.SliceStable(b.topo, func(x, y int) bool { return heads[b.topo[x]] < heads[b.topo[y]] })
+
+	b.branch = append([]int(nil), b.topo...)
+	sort.SliceStable(b.branch, func(x, y int) bool { return b.tail[b.branch[x]] > b.tail[b.branch[y]] })
+	return b
+}
+
+// seed installs an initial solution as the incumbent.
+func (b *branchAndBound) seed(starts map[string]int, makespan int) {
+	b.best = make([]int, len(b.ids))
+	for i, id := range b.ids {
+		b.best[i] = starts[id]
+	}
+	b.bestMakespan = makespan
+}
+
+// bestStarts returns the incumbent start times keyed by task ID.
+func (b *branchAndBound) bestStarts() map[string]int {
+	starts := make(map[string]int, len(b.ids))
+	for i, id := range b.ids {
+		starts[id] = b.best[i]
+	}
+	return starts
+}
+
+// run searches until the tree is exhausted, the deadline passes or ctx is
+// done, and reports whether the incumbent was proven optimal.
+func (b *branchAndBound) run(ctx context.Context, deadline time.Time) bool {
+	b.ctx, b.deadline = ctx, deadline
+	if b.bestMakespan > b.lowerBound {
+		b.dfs(0, 0, -1, 0)
+	}
+	return !b.timedOut
+}
+
+func (b *branchAndBound) dfs(depth, lastStart, lastIndex, makespan int) {
+	b.nodes++
+	if b.nodes%1024 == 0 && (time.Now().After(b.deadline) || b.ctx.Err() != nil) {
+		b.timedOut = true
+	}
+	if b.timedOut {
+		return
+	}
+
+	if depth == len(b.ids) {
+		if makespan < b.bestMakespan {
+			b.bestMakespan = makespan
+			copy(b.best, b.start)
+		}
+		return
+	}
+	if b.bound(lastStart, makespan) >= b.bestMakespan {
+		return
+	}
+
+	for _, j := range b.branch {
+		if b.start[j] >= 0 || !b.eligible(j) {
+			continue
+		}
+		ready := 0
+		for _, p := range b.preds[j] {
+			ready = max(ready, b.start[p]+b.dur[p])
+		}
+		st := b.earliestStart(ready, b.dur[j])
+
+		// Only extend lists in (start, index) order; any other order
+		// generates a schedule that is also reachable in sorted order.
+		if st < lastStart || st == lastStart && j < lastIndex {
+			continue
+		}
+		if st+b.tail[j] >= b.bestMakespan {
+			continue
+		}
+
+		b.start[j] = st
+		b.scheduled = append(b.scheduled, j)
+		b.remaining -= b.dur[j]
+		b.dfs(depth+1, st, j, max(makespan, st+b.dur[j]))
+		b.remaining += b.dur[j]
+		b.scheduled = b.scheduled[:len(b.scheduled)-1]
+		b.start[j] = -1
+
+		if b.timedOut || b.bestMakespan <= b.lowerBound {
+			return
+		}
+	}
+}
+
+func (b *branchAndBound) eligible(j int) bool {
+	for _, p := range b.preds[j] {
+		if b.start[p] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bound returns a lower bound on any completion of the current partial
+// schedule. Remaining tasks cannot start before lastStart or before their
+// dependencies finish, and their total work needs all workers from the
+// earliest such start.
+func (b *branchAndBound) bound(lastStart, makespan int) int {
+	bound := makespan
+	est := make([]int, len(b.ids))
+	minEst := -1
+	for _, i := range b.topo {
+		if b.start[i] >= 0 {
+			continue
+		}
+		e := lastStart
+		for _, p := range b.preds[i] {
+			if b.start[p] >= 0 {
+				e = max(e, b.start[p]+b.dur[p])
+			} else {
+				e = max(e, est[p]+b.dur[p])
+			}
+		}
+		est[i] = e
+		bound = max(bound, e+b.tail[i])
+		if minEst < 0 || e < minEst {
+			minEst = e
+		}
+	}
+	if minEst >= 0 {
+		bound = max(bound, minEst+ceilDiv(b.remaining, b.workers))
+	}
+	return bound
+}
+
+// earliestStart returns the first time at or after from when a task of the
+// given duration fits next to the scheduled tasks without exceeding the
+// worker count.
+func (b *branchAndBound) earliestStart(from, duration int) int {
+	candidates := []int{from}
+	for _, k := range b.scheduled {
+		if f := b.start[k] + b.dur[k]; f > from {
+			candidates = append(candidates, f)
+		}
+	}
+	sort.Ints(candidates)
+
+	for _, t := range candidates {
+		if b.fits(t, t+duration) {
+			return t
+		}
+	}
+	return candidates[len(candidates)-1] // unreachable: the last finish always fits
+}
+
+// fits reports whether fewer than workers scheduled tasks run at every
+// moment of [from, to). Usage only increases at start times, so checking
+// from and the starts inside the window is enough.
+func (b *branchAndBound) fits(from, to int) bool {
+	points := []int{from}
+	for _, k := range b.scheduled {
+		if s := b.start[k]; s > from && s < to {
+			points = append(points, s)
+		}
+	}
+	for _, t := range points {
+		running := 0
+		for _, k := range b.scheduled {
+			if b.start[k] <= t && t < b.start[k]+b.dur[k] {
+				running++
+			}
+		}
+		if running >= b.workers {
+			return false
+		}
+	}
+	return true
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
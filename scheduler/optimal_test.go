@@ -0,0 +1,231 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"wingie_case/model"
+)
+
+func TestOptimalSchedulerKnownOptima(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []spec
+		workers int
+		want    int
+	}{
+		{
+			// Longest-first fills both workers with 3 and 3, then 2 and 2,
+			// leaving the last 2 alone: 7. Splitting 3+3 / 2+2+2 gives 6.
+			name: "independent 3,3,2,2,2",
+			specs: []spec{
+				{id: "a", dur: 3}, {id: "b", dur: 3},
+				{id: "c", dur: 2}, {id: "d", dur: 2}, {id: "e", dur: 2},
+			},
+			workers: 2,
+			want:    6,
+		},
+		{
+			name: "chain",
+			specs: []spec{
+				{id: "a", dur: 2},
+				{id: "b", dur: 3, deps: []string{"a"}},
+				{id: "c", dur: 4, deps: []string{"b"}},
+			},
+			workers: 2,
+			want:    9,
+		},
+		{
+			name: "case study",
+			specs: []spec{
+				{id: "A", dur: 3}, {id: "B", dur: 2}, {id: "C", dur: 4},
+				{id: "D", dur: 5, deps: []string{"A"}},
+				{id: "E", dur: 2, deps: []string{"B", "C"}},
+				{id: "F", dur: 3, deps: []string{"D", "E"}},
+			},
+			workers: 2,
+			want:    11,
+		},
+		{
+			name: "single worker",
+			specs: []spec{
+				{id: "a", dur: 1}, {id: "b", dur: 2, deps: []string{"a"}}, {id: "c", dur: 3},
+			},
+			workers: 1,
+			want:    6,
+		},
+		{
+			// Three jobs of 2 and two of 3 on three workers: 3+2 / 3+2 / 2.
+			name: "three workers",
+			specs: []spec{
+				{id: "a", dur: 2}, {id: "b", dur: 2}, {id: "c", dur: 2},
+				{id: "d", dur: 3}, {id: "e", dur: 3},
+			},
+			workers: 3,
+			want:    5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := buildJob(t, tt.specs...)
+			result, err := NewOptimalScheduler().Schedule(job, tt.workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.MinCompletionTime != tt.want {
+				t.Errorf("completion time %d, want %d", result.MinCompletionTime, tt.want)
+			}
+			if !result.Optimal || result.LowerBound != tt.want {
+				t.Errorf("optimal %v with lower bound %d, want a proven optimum", result.Optimal, result.LowerBound)
+			}
+			checkSchedule(t, job, result, tt.workers)
+		})
+	}
+}
+
+// Small random jobs are checked against the best schedule found by trying
+// every task order.
+func TestOptimalSchedulerMatchesExhaustiveSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 40; i++ {
+		job := randomJob(rng, 4+rng.Intn(4), 0.3)
+		workers := 2 + rng.Intn(2)
+		t.Run(fmt.Sprintf("job%d", i), func(t *testing.T) {
+			result, err := NewOptimalScheduler().Schedule(job, workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := exhaustiveMakespan(job, workers)
+			if result.MinCompletionTime != want {
+				t.Errorf("completion time %d on %d workers, want %d", result.MinCompletionTime, workers, want)
+			}
+			if !result.Optimal {
+				t.Error("result is not marked optimal")
+			}
+			checkSchedule(t, job, result, workers)
+
+			heuristic, err := NewWorkerScheduler().Schedule(job, workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if heuristic.MinCompletionTime < want {
+				t.Errorf("list schedule %d beats the optimum %d", heuristic.MinCompletionTime, want)
+			}
+		})
+	}
+}
+
+// When the search cannot run to the end, the list schedule is kept and the
+// result is only marked optimal if it reaches a lower bound.
+func TestOptimalSchedulerLimits(t *testing.T) {
+	job := buildJob(t,
+		spec{id: "a", dur: 3}, spec{id: "b", dur: 3},
+		spec{id: "c", dur: 2}, spec{id: "d", dur: 2}, spec{id: "e", dur: 2},
+	)
+	result, err := NewOptimalSchedulerWithLimits(4, time.Second).Schedule(job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MinCompletionTime != 7 || result.Optimal {
+		t.Errorf("completion time %d (optimal %v), want the list schedule's 7, not optimal",
+			result.MinCompletionTime, result.Optimal)
+	}
+	if result.LowerBound != 6 {
+		t.Errorf("lower bound %d, want 6", result.LowerBound)
+	}
+}
+
+// randomJob returns a job of n tasks with durations 1-5 in which each task
+// depends on each earlier one with probability p.
+func randomJob(rng *rand.Rand, n int, p float64) *model.Job {
+	job := model.NewJob("random")
+	for i := 0; i < n; i++ {
+		var deps []string
+		for k := 0; k < i; k++ {
+			if rng.Float64() < p {
+				deps = append(deps, fmt.Sprintf("t%d", k))
+			}
+		}
+		task, _ := model.NewTask(fmt.Sprintf("t%d", i), 1+rng.Intn(5), deps)
+		job.AddTask(task)
+	}
+	return job
+}
+
+// exhaustiveMakespan returns the shortest completion time of job on
+// identical workers. It tries every task order that respects the
+// dependencies, starting each task as early as its dependencies and the
+// first free worker allow; ordering the tasks of an optimal schedule by
+// start time reproduces it, so the minimum over all orders is optimal.
+func exhaustiveMakespan(job *model.Job, workers int) int {
+	ids := make([]string, 0, job.TaskCount())
+	for id := range job.Tasks {
+		ids = append(ids, id)
+	}
+	best := -1
+	finish := make(map[string]int, len(ids))
+	freeAt := make([]int, workers)
+	var visit func(placed, makespan int)
+	visit = func(placed, makespan int) {
+		if best >= 0 && makespan >= best {
+			return
+		}
+		if placed == len(ids) {
+			best = makespan
+			return
+		}
+		for _, id := range ids {
+			if _, done := finish[id]; done {
+				continue
+			}
+			task := job.Tasks[id]
+			ready := 0
+			eligible := true
+			for _, dep := range task.Dependencies {
+				f, ok := finish[dep]
+				eligible = eligible && ok
+				ready = max(ready, f)
+			}
+			if !eligible {
+				continue
+			}
+			w := 0
+			for k := range freeAt {
+				if freeAt[k] < freeAt[w] {
+					w = k
+				}
+			}
+			previous := freeAt[w]
+			end := max(previous, ready) + task.Duration
+			finish[id], freeAt[w] = end, end
+			visit(placed+1, max(makespan, end))
+			delete(finish, id)
+			freeAt[w] = previous
+		}
+	}
+	visit(0, 0)
+	return best
+}
+
+// checkSchedule fails unless result respects the dependencies of job and
+// never runs more than workers tasks at once.
+func checkSchedule(t *testing.T, job *model.Job, result *model.ScheduleResult, workers int) {
+	t.Helper()
+	checkLinks(t, job, result)
+	if len(result.TaskSchedules) != job.TaskCount() {
+		t.Fatalf("%d task(s) scheduled, want %d", len(result.TaskSchedules), job.TaskCount())
+	}
+	for _, ts := range result.TaskSchedules {
+		running := 0
+		for _, other := range result.TaskSchedules {
+			if other.EarliestStart <= ts.EarliestStart && ts.EarliestStart < other.EarliestFinish {
+				running++
+			}
+		}
+		if running > workers {
+			t.Errorf("%d tasks run at time %d on %d worker(s)", running, ts.EarliestStart, workers)
+		}
+	}
+}
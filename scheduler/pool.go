@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"wingie_case/model"
+)
+
+// WorkerPool returns the workers that run job: its declared pool, or the
+// given number of identical workers of speed 1 when it has none.
+func WorkerPool(job *model.Job, workers int) ([]model.Worker, error) {
+	if len(job.Pool) == 0 {
+		return identicalWorkers(workers), nil
+	}
+	if workers != len(job.Pool) {
+		return nil, fmt.Errorf("job declares a pool of %d worker(s), got %d", len(job.Pool), workers)
+	}
+	return job.Pool, nil
+}
+
+// identicalWorkers returns n workers of speed 1.
+func identicalWorkers(n int) []model.Worker {
+	pool := make([]model.Worker, n)
+	for i := range pool {
+		pool[i] = model.Worker{ID: i + 1, Name: fmt.Sprintf("W%d", i+1), Speed: 1}
+	}
+	return pool
+}
+
+// scaledJob returns a copy of job, without its pool, whose durations are the
+// effective durations on workers of the given speed. With speed 1 the job
+// itself is returned.
+func scaledJob(job *model.Job, speed float64) *model.Job {
+	if speed == 1 {
+		return job
+	}
+	scaled := model.NewJob(job.Name)
+	scaled.Resources = job.Resources
+	w := model.Worker{Speed: speed}
+	for id, task := range job.Tasks {
+		copied := *task
+		copied.Duration = w.Duration(task.Duration)
+		scaled.Tasks[id] = &copied
+	}
+	return scaled
+}
+
+// CheckEligibility returns an error naming the first task, in ID order, that
+// no worker of pool can run.
+func CheckEligibility(job *model.Job, pool []model.Worker) error {
+	ids := make([]string, 0, len(job.Tasks))
+	for id := range job.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !canBeRun(job.Tasks[id], pool) {
+			return fmt.Errorf("no worker can run task '%s' (requires %s)",
+				id, strings.Join(job.Tasks[id].Requires, ", "))
+		}
+	}
+	return nil
+}
+
+// canBeRun reports whether some worker of pool can run task.
+func canBeRun(task *model.Task, pool []model.Worker) bool {
+	for _, w := range pool {
+		if w.CanRun(task) {
+			return true
+		}
+	}
+	return false
+}
+
+// finishToStart returns job with every typed link replaced by a plain
+// finish-to-start dependency; a job without typed links is returned as is.
+func finishToStart(job *model.Job) *model.Job {
+	if !job.HasTypedLinks() {
+		return job
+	}
+	plain := *job
+	plain.Tasks = make(map[string]*model.Task, len(job.Tasks))
+	for id, task := range job.Tasks {
+		copied := *task
+		copied.Links = nil
+		plain.Tasks[id] = &copied
+	}
+	return &plain
+}
+
+// fastestSpeed returns the highest speed in pool, or 1 when it is empty.
+func fastestSpeed(pool []model.Worker) float64 {
+	if len(pool) == 0 {
+		return 1
+	}
+	fastest := 0.0
+	for _, w := range pool {
+		fastest = max(fastest, w.Speed)
+	}
+	return fastest
+}
+
+// capacity returns the total speed of pool: the amount of reference work it
+// can do per time unit.
+func capacity(pool []model.Worker) float64 {
+	total := 0.0
+	for _, w := range pool {
+		total += w.Speed
+	}
+	return total
+}
@@ -0,0 +1,170 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"wingie_case/model"
+)
+
+// ReadyTask describes a task whose dependencies have all finished and that
+// is waiting for a free worker.
+type ReadyTask struct {
+	ID      string
+	ReadyAt int // time the task's last dependency finished
+	Seq     int // order in which tasks became ready, starting at 0
+}
+
+// PriorityRule decides which ready task a free worker picks next in the
+// limited-worker simulation.
+type PriorityRule interface {
+	// Name is the identifier used on the command line and in results.
+	Name() string
+	// Prepare is called once per job and returns a comparator reporting
+	// whether a should start before b. It must be a strict total order.
+	Prepare(job *model.Job) func(a, b ReadyTask) bool
+}
+
+// Names of the built-in priority rules.
+const (
+	RuleByID                 = "id"
+	RuleLongestRemainingPath = "lrp"
+	RuleLongestProcessing    = "lpt"
+	RuleMostSuccessors       = "most-successors"
+	RuleShortestProcessing   = "spt"
+	RuleFIFO                 = "fifo"
+)
+
+// DefaultPriorityRule is the rule used when none is chosen: ready tasks
+// start in ID order.
+const DefaultPriorityRule = RuleByID
+
+var builtinRules = map[string]PriorityRule{
+	RuleByID:                 scoreRule{name: RuleByID, score: noScores},
+	RuleLongestRemainingPath: scoreRule{name: RuleLongestRemainingPath, score: remainingPathLengths},
+	RuleLongestProcessing:    scoreRule{name: RuleLongestProcessing, score: longestFirst},
+	RuleMostSuccessors:       scoreRule{name: RuleMostSuccessors, score: successorCounts},
+	RuleShortestProcessing:   scoreRule{name: RuleShortestProcessing, score: shortestFirst},
+	RuleFIFO:                 fifoRule{},
+}
+
+// ParsePriorityRule returns the built-in rule with the given name.
+func ParsePriorityRule(name string) (PriorityRule, error) {
+	rule, ok := builtinRules[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown priority rule '%s' (available: %s)",
+			name, strings.Join(PriorityRuleNames(), ", "))
+	}
+	return rule, nil
+}
+
+// PriorityRuleNames returns the names of the built-in rules, sorted.
+func PriorityRuleNames() []string {
+	names := make([]string, 0, len(builtinRules))
+	for name := range builtinRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scoreRule starts the task with the highest score first; ties (and tasks
+// without a score) are broken alphabetically by task ID.
+type scoreRule struct {
+	name  string
+	score func(job *model.Job) map[string]int
+}
+
+func (r scoreRule) Name() string {
+	return r.name
+}
+
+func (r scoreRule) Prepare(job *model.Job) func(a, b ReadyTask) bool {
+	scores := r.score(job)
+	return func(a, b ReadyTask) bool {
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] > scores[b.ID]
+		}
+		return a.ID < b.ID
+	}
+}
+
+// fifoRule starts tasks in the order they became ready.
+type fifoRule struct{}
+
+func (fifoRule) Name() string {
+	return RuleFIFO
+}
+
+func (fifoRule) Prepare(*model.Job) func(a, b ReadyTask) bool {
+	return func(a, b ReadyTask) bool {
+		if a.ReadyAt != b.ReadyAt {
+			return a.ReadyAt < b.ReadyAt
+		}
+		if a.Seq != b.Seq {
+			return a.Seq < b.Seq
+		}
+		return a.ID < b.ID
+	}
+}
+
+// noScores gives every task the same score, leaving only the ID tie-break.
+func noScores(*model.Job) map[string]int {
+	return nil
+}
+
+// longestFirst scores tasks by duration.
+func longestFirst(job *model.Job) map[string]int {
+	scores := make(map[string]int, job.TaskCount())
+	for id, task := range job.Tasks {
+		scores[id] = task.Duration
+	}
+	return scores
+}
+
+// shortestFirst scores tasks by negated duration.
+func shortestFirst(job *model.Job) map[string]int {
+	scores := longestFirst(job)
+	for id := range scores {
+		scores[id] = -scores[id]
+	}
+	return scores
+}
+
+// remainingPathLengths returns, for each task, its duration plus the longest
+// chain of successors after it (the "tail" of the task in CPM terms). Typed
+// links add their gap to the chain; a tail is never negative.
+func remainingPathLengths(job *model.Job) map[string]int {
+	successors := job.SuccessorMap()
+	tail := make(map[string]int, job.TaskCount())
+
+	var visit func(id string) int
+	visit = func(id string) int {
+		if v, ok := tail[id]; ok {
+			return v
+		}
+		dur := job.Tasks[id].Duration
+		longest := 0
+		for _, succID := range successors[id] {
+			succ := job.Tasks[succID]
+			longest = max(longest, succ.Link(id).Gap(dur, succ.Duration)+visit(succID))
+		}
+		tail[id] = dur + longest
+		return tail[id]
+	}
+	for id := range job.Tasks {
+		visit(id)
+	}
+	return tail
+}
+
+// successorCounts returns the number of tasks that transitively depend on each task.
+func successorCounts(job *model.Job) map[string]int {
+	descendants := job.DescendantSets()
+	counts := make(map[string]int, job.TaskCount())
+	for id := range job.Tasks {
+		counts[id] = descendants.Count(id)
+	}
+	return counts
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePriorityRule(t *testing.T) {
+	for _, name := range PriorityRuleNames() {
+		rule, err := ParsePriorityRule(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if rule.Name() != name {
+			t.Errorf("ParsePriorityRule(%q).Name() = %q", name, rule.Name())
+		}
+	}
+	if rule, err := ParsePriorityRule(" LRP "); err != nil || rule.Name() != RuleLongestRemainingPath {
+		t.Errorf("ParsePriorityRule(\" LRP \") = %v, %v", rule, err)
+	}
+	if _, err := ParsePriorityRule("random"); err == nil || !strings.Contains(err.Error(), "available: fifo, id") {
+		t.Errorf("error = %v, want the list of rules", err)
+	}
+}
+
+// On one worker the start order is exactly the order the rule picks in.
+func TestPriorityRules(t *testing.T) {
+	job := buildJob(t,
+		spec{id: "a", dur: 1},
+		spec{id: "b", dur: 3},
+		spec{id: "c", dur: 2},
+		spec{id: "d", dur: 4, deps: []string{"c"}},
+		spec{id: "e", dur: 1, deps: []string{"a"}},
+	)
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{rule: RuleByID, want: "a b c d e"},
+		{rule: RuleLongestRemainingPath, want: "c d b a e"},
+		{rule: RuleLongestProcessing, want: "b c d a e"},
+		{rule: RuleShortestProcessing, want: "a e c b d"},
+		{rule: RuleMostSuccessors, want: "a c b d e"},
+		{rule: RuleFIFO, want: "a b c e d"},
+	}
+	for _, tt := range tests {
+		rule, err := ParsePriorityRule(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := NewWorkerSchedulerWithRule(rule).Schedule(job, 1)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.rule, err)
+		}
+		if got := strings.Join(result.ExecutionOrder, " "); got != tt.want {
+			t.Errorf("%s: execution order %s, want %s", tt.rule, got, tt.want)
+		}
+		if result.PriorityRule != tt.rule {
+			t.Errorf("%s: result names rule %q", tt.rule, result.PriorityRule)
+		}
+		if result.MinCompletionTime != 11 {
+			t.Errorf("%s: completion time %d, want 11", tt.rule, result.MinCompletionTime)
+		}
+	}
+}
+
+// With two workers the rule changes the makespan: starting the long chain
+// first finishes earlier.
+func TestPriorityRuleMakespan(t *testing.T) {
+	job := buildJob(t,
+		spec{id: "a", dur: 3},
+		spec{id: "b", dur: 3},
+		spec{id: "z", dur: 1},
+		spec{id: "z2", dur: 6, deps: []string{"z"}},
+	)
+	byID, err := NewWorkerScheduler().Schedule(job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lrp, err := NewWorkerSchedulerWithRule(builtinRules[RuleLongestRemainingPath]).Schedule(job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byID.MinCompletionTime != 10 || lrp.MinCompletionTime != 7 {
+		t.Errorf("completion times id %d, lrp %d; want 10 and 7", byID.MinCompletionTime, lrp.MinCompletionTime)
+	}
+}
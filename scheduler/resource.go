@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"wingie_case/model"
+)
+
+// ResourceScheduler schedules jobs whose tasks demand renewable resources
+// (model.Task.Resources) on top of a worker. It is a list scheduler using the
+// parallel schedule generation scheme: whenever tasks finish, the ready ones
+// are considered in the order of the priority rule, and each starts as soon
+// as a worker is free and every resource it demands has enough units left.
+// A task that does not fit is passed over for the next one, so every
+// capacity holds at all times.
+//
+// Worker pools, speeds and tags are handled as by WorkerScheduler.
+type ResourceScheduler struct {
+	rule PriorityRule
+}
+
+// NewResourceScheduler creates a scheduler that starts ready tasks in ID order.
+func NewResourceScheduler() *ResourceScheduler {
+	return &ResourceScheduler{rule: builtinRules[DefaultPriorityRule]}
+}
+
+// NewResourceSchedulerWithRule creates a scheduler that picks ready tasks
+// using rule.
+func NewResourceSchedulerWithRule(rule PriorityRule) *ResourceScheduler {
+	if rule == nil {
+		return NewResourceScheduler()
+	}
+	return &ResourceScheduler{rule: rule}
+}
+
+// Schedule returns a schedule for the job using the given number of workers
+// that never uses more of a resource than the job's capacity. The
+// simulation is used even with a worker per task, since resources may keep
+// independent tasks apart.
+func (s *ResourceScheduler) Schedule(job *model.Job, workers int) (*model.ScheduleResult, error) {
+	return s.ScheduleContext(context.Background(), job, workers)
+}
+
+// ScheduleContext is Schedule, giving up once ctx is done.
+func (s *ResourceScheduler) ScheduleContext(ctx context.Context, job *model.Job, workers int) (*model.ScheduleResult, error) {
+	if workers <= 0 {
+		return nil, fmt.Errorf("workers must be positive, got %d", workers)
+	}
+	pool, err := WorkerPool(job, workers)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckDemands(job); err != nil {
+		return nil, err
+	}
+	result, err := NewWorkerSchedulerWithRule(s.rule).scheduleLimited(ctx, job, pool, job.Resources)
+	if err != nil {
+		return nil, err
+	}
+	setPool(result, job.Pool)
+	result.Resources = job.Resources
+	return result, nil
+}
+
+// CheckDemands returns an error naming the first task, in ID order, that
+// demands more of a resource than the job has; such a task could never start.
+func CheckDemands(job *model.Job) error {
+	ids := make([]string, 0, len(job.Tasks))
+	for id := range job.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, name := range sortedResources(job.Tasks[id].Resources) {
+			if demand, capacity := job.Tasks[id].Resources[name], job.Resources[name]; demand > capacity {
+				return fmt.Errorf("task '%s' demands %d of resource '%s', which has a capacity of %d",
+					id, demand, name, capacity)
+			}
+		}
+	}
+	return nil
+}
+
+// sortedResources returns the resource names of a demand or capacity map in
+// alphabetical order.
+func sortedResources(amounts map[string]int) []string {
+	names := make([]string, 0, len(amounts))
+	for name := range amounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResourceUsage tracks the units of each resource held by running tasks.
+// A nil capacity map means no resource is constrained.
+type ResourceUsage struct {
+	capacity map[string]int
+	used     map[string]int
+}
+
+// NewResourceUsage creates a ResourceUsage with nothing in use.
+func NewResourceUsage(capacity map[string]int) *ResourceUsage {
+	return &ResourceUsage{capacity: capacity, used: make(map[string]int, len(capacity))}
+}
+
+// Fits reports whether task can start without exceeding a capacity.
+func (u *ResourceUsage) Fits(task *model.Task) bool {
+	if u.capacity == nil {
+		return true
+	}
+	for name, demand := range task.Resources {
+		if u.used[name]+demand > u.capacity[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// Acquire records that task started.
+func (u *ResourceUsage) Acquire(task *model.Task) {
+	for name, demand := range task.Resources {
+		u.used[name] += demand
+	}
+}
+
+// Release records that task finished.
+func (u *ResourceUsage) Release(task *model.Task) {
+	for name, demand := range task.Resources {
+		u.used[name] -= demand
+	}
+}
@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+// checkCapacities fails if, at any time, running tasks use more of a
+// resource than the job has.
+func checkCapacities(t *testing.T, job *model.Job, result *model.ScheduleResult) {
+	t.Helper()
+	times := make([]int, 0, len(result.TaskSchedules))
+	for _, ts := range result.TaskSchedules {
+		times = append(times, ts.EarliestStart)
+	}
+	sort.Ints(times)
+	for _, at := range times {
+		used := make(map[string]int)
+		for _, ts := range result.TaskSchedules {
+			if ts.EarliestStart <= at && at < ts.EarliestFinish {
+				for name, units := range job.Tasks[ts.TaskID].Resources {
+					used[name] += units
+				}
+			}
+		}
+		for name, units := range used {
+			if units > job.Resources[name] {
+				t.Errorf("%d of %s in use at %d, capacity %d", units, name, at, job.Resources[name])
+			}
+		}
+	}
+}
+
+func TestResourceScheduler(t *testing.T) {
+	tests := []struct {
+		name      string
+		specs     []spec
+		resources map[string]map[string]int
+		capacity  map[string]int
+		workers   int
+		want      int
+	}{
+		{
+			name:      "single license",
+			specs:     []spec{{id: "a", dur: 3}, {id: "b", dur: 3}, {id: "c", dur: 1}},
+			resources: map[string]map[string]int{"a": {"license": 1}, "b": {"license": 1}},
+			capacity:  map[string]int{"license": 1},
+			workers:   3,
+			want:      6,
+		},
+		{
+			// b does not fit next to a, so c starts first and b waits.
+			name:  "pass over",
+			specs: []spec{{id: "a", dur: 2}, {id: "b", dur: 2}, {id: "c", dur: 2}},
+			resources: map[string]map[string]int{
+				"a": {"cpu": 2}, "b": {"cpu": 3}, "c": {"cpu": 1},
+			},
+			capacity: map[string]int{"cpu": 4},
+			workers:  3,
+			want:     4,
+		},
+		{
+			name:      "dependencies",
+			specs:     []spec{{id: "a", dur: 2}, {id: "b", dur: 2, deps: []string{"a"}}, {id: "c", dur: 2}},
+			resources: map[string]map[string]int{"b": {"cpu": 1}, "c": {"cpu": 1}},
+			capacity:  map[string]int{"cpu": 1},
+			workers:   2,
+			want:      4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := buildJob(t, tt.specs...)
+			job.Resources = tt.capacity
+			for id, amounts := range tt.resources {
+				job.Tasks[id].Resources = amounts
+			}
+			result, err := NewResourceScheduler().Schedule(job, tt.workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.MinCompletionTime != tt.want {
+				t.Errorf("completion time %d, want %d", result.MinCompletionTime, tt.want)
+			}
+			checkLinks(t, job, result)
+			checkCapacities(t, job, result)
+
+			// The worker scheduler hands the job over.
+			viaWorker, err := NewWorkerScheduler().Schedule(job, tt.workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if viaWorker.MinCompletionTime != result.MinCompletionTime {
+				t.Errorf("worker scheduler: completion time %d, want %d", viaWorker.MinCompletionTime, result.MinCompletionTime)
+			}
+		})
+	}
+}
+
+func TestCheckDemands(t *testing.T) {
+	job := buildJob(t, spec{id: "a", dur: 1}, spec{id: "b", dur: 1})
+	job.Resources = map[string]int{"cpu": 2}
+	job.Tasks["a"].Resources = map[string]int{"cpu": 2}
+	if err := CheckDemands(job); err != nil {
+		t.Errorf("CheckDemands() = %v", err)
+	}
+	job.Tasks["b"].Resources = map[string]int{"cpu": 3}
+	err := CheckDemands(job)
+	if err == nil || !strings.Contains(err.Error(), "task 'b' demands 3 of resource 'cpu', which has a capacity of 2") {
+		t.Errorf("CheckDemands() = %v, want b reported", err)
+	}
+	if _, err := NewResourceScheduler().Schedule(job, 2); err == nil {
+		t.Error("scheduled a task that can never start")
+	}
+}
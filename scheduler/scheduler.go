@@ -1,9 +1,12 @@
 // Package scheduler computes a schedule for a job with a fixed number of workers.
 // When workers >= number of tasks, the result matches CPM (unlimited parallelism).
 // Otherwise a discrete-event simulation assigns tasks to workers as they become free.
+// Jobs may declare a pool of workers with different speeds (model.Worker).
+// ResourceScheduler also keeps tasks within the job's resource capacities.
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -15,27 +18,83 @@ type Scheduler interface {
 	Schedule(job *model.Job, workers int) (*model.ScheduleResult, error)
 }
 
+// ContextScheduler is a Scheduler that can be stopped: ScheduleContext
+// returns the context's error once ctx is done. The schedulers of this
+// package implement it.
+type ContextScheduler interface {
+	Scheduler
+	ScheduleContext(ctx context.Context, job *model.Job, workers int) (*model.ScheduleResult, error)
+}
+
 // WorkerScheduler schedules tasks with a limited number of workers.
-type WorkerScheduler struct{}
+// When several tasks are ready, its PriorityRule decides which one starts first.
+type WorkerScheduler struct {
+	rule PriorityRule
+}
 
+// NewWorkerScheduler creates a scheduler that starts ready tasks in ID order.
 func NewWorkerScheduler() *WorkerScheduler {
-	return &WorkerScheduler{}
+	return &WorkerScheduler{rule: builtinRules[DefaultPriorityRule]}
+}
+
+// NewWorkerSchedulerWithRule creates a scheduler that picks ready tasks using rule.
+func NewWorkerSchedulerWithRule(rule PriorityRule) *WorkerScheduler {
+	if rule == nil {
+		return NewWorkerScheduler()
+	}
+	return &WorkerScheduler{rule: rule}
 }
 
 // Schedule returns a schedule for the job using the given number of workers.
 // When workers >= task count, uses CPM (minimum completion time).
 // Otherwise simulates time and assigns ready tasks to free workers.
+// Results carry lower bounds on the completion time so the gap to the
+// optimum is known.
+//
+// A job with a pool must be scheduled on exactly its workers. When they all
+// have the same speed the durations are scaled and the above applies;
+// otherwise the simulation is always used and places each task on the
+// worker where it would finish first. The simulation is also used when
+// tasks require worker tags, and only places them on eligible workers.
+//
+// Jobs whose tasks demand resources are handed to a ResourceScheduler with
+// the same rule.
 func (s *WorkerScheduler) Schedule(job *model.Job, workers int) (*model.ScheduleResult, error) {
+	return s.ScheduleContext(context.Background(), job, workers)
+}
+
+// ScheduleContext is Schedule, giving up once ctx is done.
+func (s *WorkerScheduler) ScheduleContext(ctx context.Context, job *model.Job, workers int) (*model.ScheduleResult, error) {
 	if workers <= 0 {
 		return nil, fmt.Errorf("workers must be positive, got %d", workers)
 	}
+	if job.HasResources() {
+		return NewResourceSchedulerWithRule(s.rule).ScheduleContext(ctx, job, workers)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	pool, err := WorkerPool(job, workers)
+	if err != nil {
+		return nil, err
+	}
 
+	var result *model.ScheduleResult
+	speed, uniform := model.UniformSpeed(pool)
+	switch {
+	case !uniform || job.HasRequirements():
+		result, err = s.scheduleLimited(ctx, job, pool, nil)
 	// When we have at least as many workers as tasks, unlimited parallelism applies.
-	if workers >= job.TaskCount() {
-		return s.scheduleUnlimited(job, workers)
+	case workers >= job.TaskCount():
+		result, err = s.scheduleUnlimited(scaledJob(job, speed), workers)
+	default:
+		result, err = s.scheduleLimited(ctx, scaledJob(job, speed), identicalWorkers(workers), nil)
 	}
-
-	return s.scheduleLimited(job, workers)
+	if err != nil {
+		return nil, err
+	}
+	setPool(result, job.Pool)
+	return result, nil
 }
 
 // scheduleUnlimited runs CPM and sets Workers on the result.
@@ -50,17 +109,13 @@ func (s *WorkerScheduler) scheduleUnlimited(job *model.Job, workers int) (*model
 
 	for _, id := range order {
 		task := job.Tasks[id]
-		if !task.HasDependencies() {
-			est[id] = 0
-		} else {
-			maxFinish := 0
-			for _, depID := range task.Dependencies {
-				if eft[depID] > maxFinish {
-					maxFinish = eft[depID]
-				}
-			}
-			est[id] = maxFinish
+		// A task starts once every link allows it, and never before 0.
+		start := 0
+		for _, depID := range task.Dependencies {
+			gap := task.Link(depID).Gap(job.Tasks[depID].Duration, task.Duration)
+			start = max(start, eft[depID]+gap)
 		}
+		est[id] = start
 		eft[id] = est[id] + task.Duration
 	}
 
@@ -72,105 +127,328 @@ func (s *WorkerScheduler) scheduleUnlimited(job *model.Job, workers int) (*model
 	}
 
 	schedules := s.buildSortedSchedules(order, est, eft)
+	s.applyBackwardPass(job, order, schedules, minCompletion)
+	s.assignWorkers(schedules)
 	executionOrder := make([]string, 0, len(schedules))
 	for _, ts := range schedules {
 		executionOrder = append(executionOrder, ts.TaskID)
 	}
 
-	criticalPath := s.findCriticalPath(job, est, eft, minCompletion)
+	criticalTasks, criticalPaths, truncated := s.findCriticalPaths(job, schedules, minCompletion)
+	var criticalPath []string
+	if len(criticalPaths) > 0 {
+		criticalPath = criticalPaths[0]
+	}
 
-	return &model.ScheduleResult{
-		JobName:           job.Name,
-		Workers:           workers,
-		MinCompletionTime: minCompletion,
-		TaskSchedules:     schedules,
-		ExecutionOrder:    executionOrder,
-		CriticalPath:      criticalPath,
-	}, nil
+	result := &model.ScheduleResult{
+		JobName:                job.Name,
+		Workers:                workers,
+		MinCompletionTime:      minCompletion,
+		TaskSchedules:          schedules,
+		ExecutionOrder:         executionOrder,
+		CriticalPath:           criticalPath,
+		CriticalPaths:          criticalPaths,
+		CriticalPathsTruncated: truncated,
+		CriticalTasks:          criticalTasks,
+		WorkerTimelines:        buildWorkerTimelines(schedules, nil),
+	}
+	applyLowerBounds(result, computeLowerBounds(job, identicalWorkers(workers), nil))
+	return result, nil
 }
 
-// scheduleLimited runs a discrete-event simulation with a fixed number of workers.
-func (s *WorkerScheduler) scheduleLimited(job *model.Job, workers int) (*model.ScheduleResult, error) {
-	_, err := s.topologicalOrder(job)
+// scheduleLimited runs a discrete-event simulation with a fixed pool of workers.
+// Whenever a worker is free, the ready tasks are considered in the order of the
+// priority rule. Each goes to the worker where it would finish first, taking
+// speeds into account; when that worker is still busy the task waits for it.
+// With identical workers this is simply the lowest-numbered free worker.
+// Workers lacking a tag a task requires are never considered for it, and a
+// task only starts when its resource demands fit in the remaining capacity;
+// nil capacities leave resources unconstrained.
+func (s *WorkerScheduler) scheduleLimited(ctx context.Context, job *model.Job, pool []model.Worker, capacities map[string]int) (*model.ScheduleResult, error) {
+	order, err := s.topologicalOrder(job)
 	if err != nil {
 		return nil, err
 	}
+	if err := CheckEligibility(job, pool); err != nil {
+		return nil, err
+	}
 
 	// reverse[taskID] = tasks that depend on taskID
-	reverse := make(map[string][]string, job.TaskCount())
-	for id, task := range job.Tasks {
-		for _, depID := range task.Dependencies {
-			reverse[depID] = append(reverse[depID], id)
-		}
-	}
+	reverse := job.SuccessorMap()
+	before := s.rule.Prepare(job)
 
 	finished := make(map[string]int)
 	startTime := make(map[string]int)
-	ready := make(map[string]bool)
+	finishTime := make(map[string]int) // known as soon as the task starts
+	workerOf := make(map[string]int)
+	ready := make(map[string]ReadyTask)
+	released := make(map[string]bool) // every dependency started; waiting for the links
+	seq := 0
+	currentTime := 0
 
-	for id, task := range job.Tasks {
-		if !task.HasDependencies() {
-			ready[id] = true
+	for _, id := range order {
+		if !job.Tasks[id].HasDependencies() {
+			ready[id] = ReadyTask{ID: id, ReadyAt: 0, Seq: seq}
+			seq++
 		}
 	}
 
+	// placement is a task planned on a worker, which is an index in pool.
+	type placement struct {
+		worker, start, finish int
+	}
+	// notBefore returns the earliest start the links of a released task
+	// allow when it runs for duration. Dependencies in planned are taken at
+	// their planned times.
+	notBefore := func(task *model.Task, duration int, planned map[string]placement) int {
+		at := 0
+		for _, depID := range task.Dependencies {
+			start, finish := startTime[depID], finishTime[depID]
+			if p, ok := planned[depID]; ok {
+				start, finish = p.start, p.finish
+			}
+			gap := task.Link(depID).Gap(finish-start, duration)
+			at = max(at, finish+gap)
+		}
+		return at
+	}
+	// releaseAt returns the earliest time any eligible worker could start a
+	// released task.
+	releaseAt := func(task *model.Task) int {
+		at := -1
+		for _, worker := range pool {
+			if worker.CanRun(task) {
+				if t := notBefore(task, worker.Duration(task.Duration), nil); at < 0 || t < at {
+					at = t
+				}
+			}
+		}
+		return at
+	}
+	// promote moves a released task to the ready list once its links allow
+	// it to start. A dependency finishing now must have been completed
+	// first, so that tasks become ready in completion order.
+	promote := func(id string) bool {
+		task := job.Tasks[id]
+		for _, depID := range task.Dependencies {
+			if _, done := finished[depID]; !done && finishTime[depID] <= currentTime {
+				return false
+			}
+		}
+		if releaseAt(task) > currentTime {
+			return false
+		}
+		delete(released, id)
+		ready[id] = ReadyTask{ID: id, ReadyAt: currentTime, Seq: seq}
+		seq++
+		return true
+	}
+	// promoteAll promotes the released tasks in ID order and reports whether
+	// any became ready.
+	promoteAll := func() bool {
+		ids := make([]string, 0, len(released))
+		for id := range released {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		promoted := false
+		for _, id := range ids {
+			promoted = promote(id) || promoted
+		}
+		return promoted
+	}
+
 	type slot struct {
 		taskID     string
+		worker     int // index in pool
 		finishTime int
 	}
-	running := make([]slot, 0, workers)
+	running := make([]slot, 0, len(pool))
+
+	// freeAt[w] is when worker w+1 finishes the last task given to it; busy
+	// tells whether it has one. A task planned to start later keeps its
+	// worker busy from the moment it is given.
+	freeAt := make([]int, len(pool))
+	busy := make([]bool, len(pool))
+	usage := NewResourceUsage(capacities)
 	var executionOrder []string
-	currentTime := 0
 
-	for {
-		// Assign as many ready tasks as we have free workers
-		readyList := make([]string, 0, len(ready))
-		for id := range ready {
-			readyList = append(readyList, id)
+	// assign gives a task its placement and releases the successors whose
+	// dependencies have all started. The caller acquires its resources.
+	assign := func(id string, p placement) {
+		delete(ready, id)
+		delete(released, id)
+		busy[p.worker], freeAt[p.worker] = true, p.finish
+		startTime[id], finishTime[id] = p.start, p.finish
+		workerOf[id] = pool[p.worker].ID
+		running = append(running, slot{taskID: id, worker: p.worker, finishTime: p.finish})
+		executionOrder = append(executionOrder, id)
+		for _, nextID := range reverse[id] {
+			if allStarted(job.Tasks[nextID], startTime) {
+				released[nextID] = true
+			}
 		}
-		sort.Strings(readyList)
-
-		for len(running) < workers && len(readyList) > 0 {
-			id := readyList[0]
-			readyList = readyList[1:]
-			delete(ready, id)
+	}
 
+	// A lead (e.g. SS-1) can let a task start before a dependency does. Such
+	// a task is planned together with its dependencies that have not started
+	// but have all of their own dependencies started: each of them on the
+	// worker where it finishes first, then the task on what is left. When
+	// the task can start now, ahead of them, all are assigned at once, the
+	// dependencies at their planned times, so that every link holds.
+	leads := job.HasTypedLinks()
+	// planLead returns the plan for a task with unstarted dependencies,
+	// including the task itself, or false when the task would not start
+	// before all of them anyway.
+	planLead := func(task *model.Task) (map[string]placement, bool) {
+		planFreeAt := append([]int(nil), freeAt...)
+		planBusy := append([]bool(nil), busy...)
+		planned := make(map[string]placement)
+		first := -1
+		for _, depID := range task.Dependencies {
+			if _, ok := startTime[depID]; ok {
+				continue
+			}
+			dep := job.Tasks[depID]
+			if task.Link(depID).FinishesFirst() || !allStarted(dep, startTime) {
+				return nil, false
+			}
+			w, start, finish := earliestFinish(pool, planFreeAt, planBusy, currentTime, dep,
+				func(d int) int { return notBefore(dep, d, nil) })
+			planned[depID] = placement{worker: w, start: start, finish: finish}
+			planBusy[w], planFreeAt[w] = true, finish
+			if first < 0 || start < first {
+				first = start
+			}
+		}
+		w, start, finish := earliestFinish(pool, planFreeAt, planBusy, currentTime, task,
+			func(d int) int { return notBefore(task, d, planned) })
+		if start >= first {
+			return nil, false
+		}
+		planned[task.ID] = placement{worker: w, start: start, finish: finish}
+		return planned, true
+	}
+	// acquireAll acquires the resources of the planned tasks if they fit
+	// together, and reports whether they did.
+	acquireAll := func(plan map[string]placement) bool {
+		var held []*model.Task
+		for id := range plan {
 			task := job.Tasks[id]
-			finish := currentTime + task.Duration
-			startTime[id] = currentTime
-			running = append(running, slot{taskID: id, finishTime: finish})
-			executionOrder = append(executionOrder, id)
+			if !usage.Fits(task) {
+				for _, t := range held {
+					usage.Release(t)
+				}
+				return false
+			}
+			usage.Acquire(task)
+			held = append(held, task)
 		}
+		return true
+	}
 
-		if len(running) == 0 {
-			break
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
+		// Assign as many ready tasks as we have free workers. Starting a task
+		// can make others ready at once (start-to-start links), so repeat
+		// until no more become ready. wake is the earliest later time at which
+		// a task that had to wait could start.
+		wake := -1
+		for {
+			readyList := make([]ReadyTask, 0, len(ready))
+			for _, rt := range ready {
+				readyList = append(readyList, rt)
+			}
+			sort.Slice(readyList, func(i, j int) bool {
+				return before(readyList[i], readyList[j])
+			})
 
-		// Advance to next completion event
-		minFinish := running[0].finishTime
-		for _, sl := range running[1:] {
-			if sl.finishTime < minFinish {
-				minFinish = sl.finishTime
+			for _, rt := range readyList {
+				if allBusy(busy) {
+					break
+				}
+				task := job.Tasks[rt.ID]
+				if !usage.Fits(task) {
+					continue // not enough of a resource left: wait for it
+				}
+				w, start, finish := earliestFinish(pool, freeAt, busy, currentTime, task,
+					func(d int) int { return notBefore(task, d, nil) })
+				if start > currentTime {
+					// the best worker is busy or the links hold the task back: wait
+					if wake < 0 || start < wake {
+						wake = start
+					}
+					continue
+				}
+				usage.Acquire(task)
+				assign(rt.ID, placement{worker: w, start: start, finish: finish})
+			}
+
+			assigned := false
+			for _, id := range order {
+				task := job.Tasks[id]
+				if _, ok := startTime[id]; !leads || ok || allStarted(task, startTime) {
+					continue
+				}
+				plan, ok := planLead(task)
+				if !ok {
+					continue
+				}
+				if at := plan[id].start; at > currentTime {
+					if wake < 0 || at < wake {
+						wake = at
+					}
+					continue
+				}
+				if !acquireAll(plan) {
+					continue
+				}
+				for _, depID := range task.Dependencies {
+					if p, ok := plan[depID]; ok {
+						assign(depID, p)
+					}
+				}
+				assign(id, plan[id])
+				assigned = true
+			}
+			if !promoteAll() && !assigned {
+				break
 			}
 		}
-		currentTime = minFinish
 
-		// Complete all tasks that finish at currentTime
+		// Advance to the next event: a completion, or a time at which a
+		// waiting or released task may start.
+		next := wake
+		for _, sl := range running {
+			if next < 0 || sl.finishTime < next {
+				next = sl.finishTime
+			}
+		}
+		for id := range released {
+			if t := releaseAt(job.Tasks[id]); t > currentTime && (next < 0 || t < next) {
+				next = t
+			}
+		}
+		if next < 0 {
+			break
+		}
+		currentTime = next
+
+		// Complete all tasks that finish at currentTime. A worker stays busy
+		// if it was given a task planned to start later.
 		newRunning := running[:0]
 		for _, sl := range running {
 			if sl.finishTime == currentTime {
 				finished[sl.taskID] = currentTime
-				for _, nextID := range reverse[sl.taskID] {
-					task := job.Tasks[nextID]
-					allDone := true
-					for _, depID := range task.Dependencies {
-						if _, ok := finished[depID]; !ok {
-							allDone = false
-							break
-						}
-					}
-					if allDone {
-						ready[nextID] = true
+				busy[sl.worker] = freeAt[sl.worker] > currentTime
+				usage.Release(job.Tasks[sl.taskID])
+				next := append([]string(nil), reverse[sl.taskID]...)
+				sort.Strings(next)
+				for _, nextID := range next {
+					if released[nextID] {
+						promote(nextID)
 					}
 				}
 			} else {
@@ -178,6 +456,10 @@ func (s *WorkerScheduler) scheduleLimited(job *model.Job, workers int) (*model.S
 			}
 		}
 		running = newRunning
+		promoteAll()
+	}
+	if len(startTime) < job.TaskCount() {
+		return nil, fmt.Errorf("could not schedule %d of %d task(s)", job.TaskCount()-len(startTime), job.TaskCount())
 	}
 
 	// Build TaskSchedules sorted by start time
@@ -189,6 +471,7 @@ func (s *WorkerScheduler) scheduleLimited(job *model.Job, workers int) (*model.S
 			TaskID:         id,
 			EarliestStart:  start,
 			EarliestFinish: finish,
+			WorkerID:       workerOf[id],
 		})
 	}
 	sort.Slice(schedules, func(i, j int) bool {
@@ -197,6 +480,7 @@ func (s *WorkerScheduler) scheduleLimited(job *model.Job, workers int) (*model.S
 		}
 		return schedules[i].TaskID < schedules[j].TaskID
 	})
+	s.applyBackwardPass(job, order, schedules, currentTime)
 
 	// ExecutionOrder: sorted by start time (same time = alphabetical)
 	executionOrderSorted := make([]string, 0, len(schedules))
@@ -204,47 +488,321 @@ func (s *WorkerScheduler) scheduleLimited(job *model.Job, workers int) (*model.S
 		executionOrderSorted = append(executionOrderSorted, ts.TaskID)
 	}
 
-	return &model.ScheduleResult{
+	result := &model.ScheduleResult{
 		JobName:           job.Name,
-		Workers:           workers,
+		Workers:           len(pool),
 		MinCompletionTime: currentTime,
 		TaskSchedules:     schedules,
 		ExecutionOrder:    executionOrderSorted,
 		CriticalPath:      nil, // not computed for limited workers
-	}, nil
+		WorkerTimelines:   buildWorkerTimelines(schedules, nil),
+		PriorityRule:      s.rule.Name(),
+	}
+	applyLowerBounds(result, computeLowerBounds(job, pool, capacities))
+	return result, nil
 }
 
-func (s *WorkerScheduler) findCriticalPath(job *model.Job, est, eft map[string]int, minCompletion int) []string {
-	var endTaskID string
-	for id, f := range eft {
-		if f == minCompletion {
-			endTaskID = id
-			break
+// earliestFinish returns the index of the eligible worker in pool on which
+// task, ready at now, finishes first, and when it would start and finish
+// there. notBefore gives the earliest start the task's links allow for a
+// duration. Ties go to a worker that can start now, then to the one with
+// fewer tags, keeping tagged workers available for the tasks that need them,
+// then to the lowest index. The task must have an eligible worker.
+func earliestFinish(pool []model.Worker, freeAt []int, busy []bool, now int, task *model.Task,
+	notBefore func(duration int) int) (int, int, int) {
+	best, bestStart, bestFinish := -1, 0, 0
+	for w, worker := range pool {
+		if !worker.CanRun(task) {
+			continue
+		}
+		duration := worker.Duration(task.Duration)
+		start := now
+		if busy[w] {
+			start = freeAt[w]
+		}
+		start = max(start, notBefore(duration))
+		finish := start + duration
+		better := best < 0 || finish < bestFinish
+		if !better && finish == bestFinish {
+			better = bestStart > now && start == now ||
+				(bestStart > now) == (start > now) && len(worker.Tags) < len(pool[best].Tags)
+		}
+		if better {
+			best, bestStart, bestFinish = w, start, finish
 		}
 	}
+	return best, bestStart, bestFinish
+}
 
-	path := []string{endTaskID}
-	currentID := endTaskID
+// allBusy reports whether every worker has a task.
+func allBusy(busy []bool) bool {
+	for _, b := range busy {
+		if !b {
+			return false
+		}
+	}
+	return true
+}
 
-	for {
-		task := job.Tasks[currentID]
-		if !task.HasDependencies() {
-			break
+// allStarted reports whether every dependency of task has a start time.
+func allStarted(task *model.Task, startTime map[string]int) bool {
+	for _, depID := range task.Dependencies {
+		if _, ok := startTime[depID]; !ok {
+			return false
 		}
-		found := false
-		for _, depID := range task.Dependencies {
-			if eft[depID] == est[currentID] {
-				path = append([]string{depID}, path...)
-				currentID = depID
-				found = true
+	}
+	return true
+}
+
+// resultFromStarts builds a limited-worker result from fixed start times,
+// as produced by OptimalScheduler. Workers are assigned by assignWorkers.
+func (s *WorkerScheduler) resultFromStarts(job *model.Job, workers int, starts map[string]int) (*model.ScheduleResult, error) {
+	timings := make(map[string]Timing, len(starts))
+	for id, start := range starts {
+		timings[id] = Timing{Start: start, Finish: start + job.Tasks[id].Duration}
+	}
+	result, err := ResultFromTimings(job, workers, timings)
+	if err != nil {
+		return nil, err
+	}
+	applyLowerBounds(result, computeLowerBounds(job, identicalWorkers(workers), nil))
+	return result, nil
+}
+
+// Timing is the placement of one task: when it started and finished and,
+// if known, which worker ran it. Executed tasks also carry their outcome.
+type Timing struct {
+	Start    int
+	Finish   int
+	WorkerID int // 1-based; 0 lets ResultFromTimings assign one
+	Status   model.TaskStatus
+	Attempts int
+}
+
+// ResultFromTimings builds a result from fixed placements, such as the
+// measured times of an executed job. Durations are taken from the timings,
+// not from the job. Latest times and floats come from a backward pass over
+// them; critical paths and lower bounds are not computed. Every task of job
+// needs a timing; tasks that did not run (see model.TaskStatus.Ran) get one
+// of zero length and no worker. Dependencies are taken as plain
+// finish-to-start links, which is how the executor runs them. The job's
+// pool, if any, is recorded on the result.
+func ResultFromTimings(job *model.Job, workers int, timings map[string]Timing) (*model.ScheduleResult, error) {
+	job = finishToStart(job)
+	s := NewWorkerScheduler()
+	order, err := s.topologicalOrder(job)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := 0
+	starts := make(map[string]int, len(timings))
+	finish := make(map[string]int, len(timings))
+	assigned := true
+	for _, id := range order {
+		t, ok := timings[id]
+		if !ok {
+			return nil, fmt.Errorf("no timing for task '%s'", id)
+		}
+		starts[id], finish[id] = t.Start, t.Finish
+		completion = max(completion, t.Finish)
+		assigned = assigned && (t.WorkerID > 0 || !t.Status.Ran())
+	}
+
+	schedules := s.buildSortedSchedules(order, starts, finish)
+	s.applyBackwardPass(job, order, schedules, completion)
+	for i := range schedules {
+		t := timings[schedules[i].TaskID]
+		schedules[i].Status, schedules[i].Attempts = t.Status, t.Attempts
+		if assigned {
+			schedules[i].WorkerID = t.WorkerID
+		}
+	}
+	if !assigned {
+		s.assignWorkers(schedules)
+	}
+
+	executionOrder := make([]string, 0, len(schedules))
+	for _, ts := range schedules {
+		executionOrder = append(executionOrder, ts.TaskID)
+	}
+
+	return &model.ScheduleResult{
+		JobName:           job.Name,
+		Workers:           workers,
+		MinCompletionTime: completion,
+		TaskSchedules:     schedules,
+		ExecutionOrder:    executionOrder,
+		WorkerTimelines:   buildWorkerTimelines(schedules, job.Pool),
+		Pool:              job.Pool,
+	}, nil
+}
+
+// applyBackwardPass fills LatestStart, LatestFinish, TotalFloat and FreeFloat
+// on schedules. It walks the topological order in reverse: a task must finish
+// before its earliest-needed successor starts late, or by completionTime when
+// nothing depends on it. Typed links shift these times by their gap (see
+// model.Link.Gap), computed from the scheduled durations.
+//
+// With limited workers the floats are measured against the simulated plan and
+// only consider dependencies, not worker availability.
+func (s *WorkerScheduler) applyBackwardPass(job *model.Job, order []string, schedules []model.TaskSchedule, completionTime int) {
+	index := make(map[string]int, len(schedules))
+	for i, ts := range schedules {
+		index[ts.TaskID] = i
+	}
+
+	successors := job.SuccessorMap()
+
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		ts := &schedules[index[id]]
+
+		latestFinish := completionTime
+		nextStart := completionTime
+		for _, succID := range successors[id] {
+			succ := schedules[index[succID]]
+			gap := job.Tasks[succID].Link(id).Gap(ts.EarliestFinish-ts.EarliestStart, succ.EarliestFinish-succ.EarliestStart)
+			latestFinish = min(latestFinish, succ.LatestStart-gap)
+			nextStart = min(nextStart, succ.EarliestStart-gap)
+		}
+
+		ts.LatestFinish = latestFinish
+		ts.LatestStart = latestFinish - (ts.EarliestFinish - ts.EarliestStart)
+		ts.TotalFloat = ts.LatestStart - ts.EarliestStart
+		ts.FreeFloat = nextStart - ts.EarliestFinish
+	}
+}
+
+// assignWorkers gives each task in schedules (sorted by start time) the
+// lowest-numbered worker that is idle at its start. With unlimited
+// parallelism this uses as many workers as the peak number of overlapping tasks.
+// Tasks of an executed job that did not run get no worker.
+func (s *WorkerScheduler) assignWorkers(schedules []model.TaskSchedule) {
+	var freeAt []int // freeAt[w-1] = time worker w finishes its last task
+	for i := range schedules {
+		ts := &schedules[i]
+		ts.WorkerID = 0
+		if !ts.Status.Ran() {
+			continue
+		}
+		for w, t := range freeAt {
+			if t <= ts.EarliestStart {
+				ts.WorkerID = w + 1
 				break
 			}
 		}
-		if !found {
-			break
+		if ts.WorkerID == 0 {
+			freeAt = append(freeAt, 0)
+			ts.WorkerID = len(freeAt)
+		}
+		freeAt[ts.WorkerID-1] = ts.EarliestFinish
+	}
+}
+
+// buildWorkerTimelines groups schedules (sorted by start time) by worker.
+// With a pool every declared worker gets a timeline, idle or not; otherwise
+// only the workers that run at least one task do. Tasks that did not run
+// (worker 0) are on no timeline.
+func buildWorkerTimelines(schedules []model.TaskSchedule, pool []model.Worker) []model.WorkerTimeline {
+	byWorker := make(map[int][]model.TaskSchedule)
+	var ids []int
+	for _, ts := range schedules {
+		if ts.WorkerID <= 0 {
+			continue
+		}
+		if _, seen := byWorker[ts.WorkerID]; !seen {
+			ids = append(ids, ts.WorkerID)
+		}
+		byWorker[ts.WorkerID] = append(byWorker[ts.WorkerID], ts)
+	}
+	if len(pool) > 0 {
+		ids = ids[:0]
+		for _, w := range pool {
+			ids = append(ids, w.ID)
 		}
 	}
-	return path
+	sort.Ints(ids)
+
+	timelines := make([]model.WorkerTimeline, 0, len(ids))
+	for _, id := range ids {
+		timelines = append(timelines, model.WorkerTimeline{WorkerID: id, Tasks: byWorker[id]})
+	}
+	return timelines
+}
+
+// setPool records the job's declared workers on result and gives each of
+// them a timeline.
+func setPool(result *model.ScheduleResult, pool []model.Worker) {
+	result.Pool = pool
+	result.WorkerTimelines = buildWorkerTimelines(result.TaskSchedules, pool)
+}
+
+// MaxCriticalPaths caps path enumeration; highly parallel jobs can have
+// exponentially many critical paths. Results with more set
+// CriticalPathsTruncated.
+const MaxCriticalPaths = 100
+
+// findCriticalPaths returns the tasks with zero total float and every path
+// through them from a task starting at 0 to a task finishing at
+// minCompletion. A critical edge links dep -> task when the dependency
+// finishes exactly when the task starts, or for typed links when the link
+// leaves no slack. Results are sorted, so ties are reported
+// deterministically. At most MaxCriticalPaths paths are returned; truncated
+// reports whether there are more.
+func (s *WorkerScheduler) findCriticalPaths(job *model.Job, schedules []model.TaskSchedule, minCompletion int) (critical []string, paths [][]string, truncated bool) {
+	byID := make(map[string]model.TaskSchedule, len(schedules))
+	for _, ts := range schedules {
+		byID[ts.TaskID] = ts
+		if ts.TotalFloat == 0 {
+			critical = append(critical, ts.TaskID)
+		}
+	}
+	sort.Strings(critical)
+
+	next := make(map[string][]string, len(critical))
+	for _, id := range critical {
+		task := byID[id]
+		for _, depID := range job.Tasks[id].Dependencies {
+			dep, ok := byID[depID]
+			if !ok || dep.TotalFloat != 0 {
+				continue
+			}
+			gap := job.Tasks[id].Link(depID).Gap(dep.EarliestFinish-dep.EarliestStart, task.EarliestFinish-task.EarliestStart)
+			if dep.EarliestFinish+gap == task.EarliestStart {
+				next[depID] = append(next[depID], id)
+			}
+		}
+	}
+	for id := range next {
+		sort.Strings(next[id])
+	}
+
+	var walk func(path []string)
+	walk = func(path []string) {
+		if truncated {
+			return
+		}
+		last := path[len(path)-1]
+		if byID[last].EarliestFinish == minCompletion {
+			if len(paths) == MaxCriticalPaths {
+				truncated = true
+				return
+			}
+			paths = append(paths, append([]string(nil), path...))
+			return
+		}
+		for _, id := range next[last] {
+			walk(append(path, id))
+		}
+	}
+	for _, id := range critical {
+		if byID[id].EarliestStart == 0 {
+			walk([]string{id})
+		}
+	}
+
+	return critical, paths, truncated
 }
 
 func (s *WorkerScheduler) buildSortedSchedules(order []string, est, eft map[string]int) []model.TaskSchedule {
@@ -265,47 +823,24 @@ func (s *WorkerScheduler) buildSortedSchedules(order []string, est, eft map[stri
 	return schedules
 }
 
+// topologicalOrder returns the tasks in dependency order, smallest ID first
+// among ready tasks. Unlike model.Job.TopologicalOrder it fails on
+// dependencies the graph methods would ignore, since scheduling needs them.
 func (s *WorkerScheduler) topologicalOrder(job *model.Job) ([]string, error) {
-	indegree := make(map[string]int, job.TaskCount())
-	adjacency := make(map[string][]string, job.TaskCount())
-
-	for id := range job.Tasks {
-		indegree[id] = 0
-	}
 	for id, task := range job.Tasks {
 		for _, depID := range task.Dependencies {
-			adjacency[depID] = append(adjacency[depID], id)
-			indegree[id]++
-		}
-	}
-
-	queue := make([]string, 0, job.TaskCount())
-	for id, deg := range indegree {
-		if deg == 0 {
-			queue = append(queue, id)
-		}
-	}
-	sort.Strings(queue)
-
-	order := make([]string, 0, job.TaskCount())
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		order = append(order, current)
-
-		neighbors := adjacency[current]
-		sort.Strings(neighbors)
-		for _, neighbor := range neighbors {
-			indegree[neighbor]--
-			if indegree[neighbor] == 0 {
-				queue = append(queue, neighbor)
-				sort.Strings(queue)
+			if _, ok := job.Tasks[depID]; !ok || depID == id {
+				return nil, fmt.Errorf("topological sort failed: task '%s' has invalid dependency '%s'", id, depID)
 			}
 		}
 	}
 
-	if len(order) != job.TaskCount() {
-		return nil, fmt.Errorf("topological sort failed: graph contains a cycle")
+	order, err := job.TopologicalOrder()
+	if err != nil {
+		if cycleErr := job.DetectCycle(); cycleErr != nil {
+			return nil, fmt.Errorf("topological sort failed: %w", cycleErr)
+		}
+		return nil, fmt.Errorf("topological sort failed: %w", err)
 	}
 	return order, nil
 }
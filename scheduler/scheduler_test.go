@@ -0,0 +1,464 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"wingie_case/model"
+)
+
+// spec describes one task of a test job. Links maps a dependency to its
+// link notation, e.g. "SS-1".
+type spec struct {
+	id    string
+	dur   int
+	deps  []string
+	links map[string]string
+}
+
+func buildJob(t *testing.T, specs ...spec) *model.Job {
+	t.Helper()
+	job := model.NewJob("test")
+	for _, s := range specs {
+		task, err := model.NewTask(s.id, s.dur, s.deps)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for dep, notation := range s.links {
+			link, err := model.ParseLink(notation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if task.Links == nil {
+				task.Links = map[string]model.Link{}
+			}
+			task.Links[dep] = link
+		}
+		if err := job.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return job
+}
+
+// caseStudy builds the job of the examples directory.
+func caseStudy(t *testing.T) *model.Job {
+	t.Helper()
+	return buildJob(t,
+		spec{id: "A", dur: 3},
+		spec{id: "B", dur: 2},
+		spec{id: "C", dur: 4},
+		spec{id: "D", dur: 5, deps: []string{"A"}},
+		spec{id: "E", dur: 2, deps: []string{"B", "C"}},
+		spec{id: "F", dur: 3, deps: []string{"D", "E"}},
+	)
+}
+
+func startsOf(result *model.ScheduleResult) map[string]int {
+	starts := make(map[string]int, len(result.TaskSchedules))
+	for _, ts := range result.TaskSchedules {
+		starts[ts.TaskID] = ts.EarliestStart
+	}
+	return starts
+}
+
+// checkLinks fails unless every task starts at or after 0 and every link of
+// job holds in result.
+func checkLinks(t *testing.T, job *model.Job, result *model.ScheduleResult) {
+	t.Helper()
+	schedules := make(map[string]model.TaskSchedule, len(result.TaskSchedules))
+	for _, ts := range result.TaskSchedules {
+		schedules[ts.TaskID] = ts
+	}
+	for id, task := range job.Tasks {
+		ts := schedules[id]
+		if ts.EarliestStart < 0 {
+			t.Errorf("%s starts at %d", id, ts.EarliestStart)
+		}
+		for _, depID := range task.Dependencies {
+			dep := schedules[depID]
+			link := task.Link(depID)
+			gap := link.Gap(dep.EarliestFinish-dep.EarliestStart, ts.EarliestFinish-ts.EarliestStart)
+			if ts.EarliestStart < dep.EarliestFinish+gap {
+				t.Errorf("%s starts at %d, link %s from %s allows %d at the earliest",
+					id, ts.EarliestStart, link, depID, dep.EarliestFinish+gap)
+			}
+		}
+	}
+}
+
+// The dependency a (4) is held back by x (5), so it runs from 5 to 9, and b
+// (2) follows it through the link under test.
+func TestTypedLinks(t *testing.T) {
+	tests := []struct {
+		link      string
+		cpm       int // start of b with a worker per task
+		simulated int // start of b on two workers
+	}{
+		{link: "FS-1", cpm: 8, simulated: 8},
+		{link: "SS-1", cpm: 4, simulated: 4},
+		{link: "FF-1", cpm: 6, simulated: 6},
+		{link: "SF-1", cpm: 2, simulated: 2},
+		{link: "FS+2", cpm: 11, simulated: 11},
+		{link: "SS+2", cpm: 7, simulated: 7},
+		{link: "FF+2", cpm: 9, simulated: 9},
+		{link: "SF+2", cpm: 5, simulated: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			job := buildJob(t,
+				spec{id: "x", dur: 5},
+				spec{id: "a", dur: 4, deps: []string{"x"}},
+				spec{id: "b", dur: 2, deps: []string{"a"}, links: map[string]string{"a": tt.link}},
+			)
+			for _, run := range []struct {
+				name    string
+				workers int
+				want    int
+			}{
+				{"cpm", 3, tt.cpm},
+				{"simulated", 2, tt.simulated},
+			} {
+				result, err := NewWorkerScheduler().Schedule(job, run.workers)
+				if err != nil {
+					t.Fatalf("%s: %v", run.name, err)
+				}
+				if got := startsOf(result)["b"]; got != run.want {
+					t.Errorf("%s: b starts at %d, want %d", run.name, got, run.want)
+				}
+				if got := startsOf(result)["a"]; got != 5 {
+					t.Errorf("%s: a starts at %d, want 5", run.name, got)
+				}
+				checkLinks(t, job, result)
+				if result.LowerBound > result.MinCompletionTime {
+					t.Errorf("%s: lower bound %d exceeds completion time %d", run.name, result.LowerBound, result.MinCompletionTime)
+				}
+			}
+		})
+	}
+}
+
+// A lead never lets a task start before time 0.
+func TestTypedLinksStartAtZero(t *testing.T) {
+	for _, notation := range []string{"FS-9", "SS-9", "FF-9", "SF-9"} {
+		t.Run(notation, func(t *testing.T) {
+			job := buildJob(t,
+				spec{id: "a", dur: 3},
+				spec{id: "b", dur: 2, deps: []string{"a"}, links: map[string]string{"a": notation}},
+			)
+			result, err := NewWorkerScheduler().Schedule(job, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := startsOf(result)["b"]; got != 0 {
+				t.Errorf("b starts at %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestWorkerSchedulerLimited(t *testing.T) {
+	job := caseStudy(t)
+	tests := []struct {
+		workers int
+		want    int
+	}{
+		{workers: 1, want: 19},
+		{workers: 2, want: 11},
+		{workers: 6, want: 11},
+	}
+	for _, tt := range tests {
+		result, err := NewWorkerScheduler().Schedule(job, tt.workers)
+		if err != nil {
+			t.Fatalf("%d worker(s): %v", tt.workers, err)
+		}
+		if result.MinCompletionTime != tt.want {
+			t.Errorf("%d worker(s): completion time %d, want %d", tt.workers, result.MinCompletionTime, tt.want)
+		}
+		checkLinks(t, job, result)
+	}
+}
+
+// Tasks take ceil(duration / speed) on a worker and go to the one that
+// finishes them first.
+func TestWorkerSchedulerPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     *model.Job
+		pool    []model.Worker
+		want    int
+		workers map[string]int
+	}{
+		{
+			name: "uniform speed",
+			job:  caseStudy(t),
+			pool: []model.Worker{{ID: 1, Speed: 2}, {ID: 2, Speed: 2}},
+			want: 7,
+		},
+		{
+			// b needs the slow worker's gpu although the fast one is free.
+			name: "tags",
+			job: func() *model.Job {
+				job := buildJob(t, spec{id: "a", dur: 2}, spec{id: "b", dur: 2})
+				job.Tasks["b"].Requires = []string{"gpu"}
+				return job
+			}(),
+			pool:    []model.Worker{{ID: 1, Speed: 1, Tags: []string{"gpu"}}, {ID: 2, Speed: 2}},
+			want:    2,
+			workers: map[string]int{"a": 2, "b": 1},
+		},
+		{
+			name:    "mixed speeds",
+			job:     buildJob(t, spec{id: "a", dur: 4}, spec{id: "b", dur: 2, deps: []string{"a"}}),
+			pool:    []model.Worker{{ID: 1, Name: "slow", Speed: 1}, {ID: 2, Name: "fast", Speed: 2}},
+			want:    3,
+			workers: map[string]int{"a": 2, "b": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.job.Pool = tt.pool
+			result, err := NewWorkerScheduler().Schedule(tt.job, len(tt.pool))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.MinCompletionTime != tt.want {
+				t.Errorf("completion time %d, want %d", result.MinCompletionTime, tt.want)
+			}
+			checkLinks(t, tt.job, result)
+			for _, ts := range result.TaskSchedules {
+				if want, ok := tt.workers[ts.TaskID]; ok && ts.WorkerID != want {
+					t.Errorf("%s on worker %d, want %d", ts.TaskID, ts.WorkerID, want)
+				}
+			}
+		})
+	}
+
+	job := caseStudy(t)
+	job.Pool = []model.Worker{{ID: 1, Speed: 1}}
+	if _, err := NewWorkerScheduler().Schedule(job, 2); err == nil {
+		t.Error("scheduling a pool of 1 on 2 workers succeeded")
+	}
+}
+
+// Every scheduler gives up with the context's error once it is done.
+func TestScheduleContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, s := range []struct {
+		name    string
+		sched   ContextScheduler
+		workers int
+	}{
+		{"limited", NewWorkerScheduler(), 2},
+		{"unlimited", NewWorkerScheduler(), 6},
+		{"resource", NewResourceScheduler(), 2},
+		{"optimal", NewOptimalScheduler(), 2},
+	} {
+		if _, err := s.sched.ScheduleContext(ctx, caseStudy(t), s.workers); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: error %v, want %v", s.name, err, context.Canceled)
+		}
+	}
+}
+
+func TestBackwardPass(t *testing.T) {
+	result, err := NewWorkerScheduler().Schedule(caseStudy(t), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]model.TaskSchedule{
+		"A": {EarliestStart: 0, EarliestFinish: 3, LatestStart: 0, LatestFinish: 3, TotalFloat: 0, FreeFloat: 0},
+		"B": {EarliestStart: 0, EarliestFinish: 2, LatestStart: 4, LatestFinish: 6, TotalFloat: 4, FreeFloat: 2},
+		"C": {EarliestStart: 0, EarliestFinish: 4, LatestStart: 2, LatestFinish: 6, TotalFloat: 2, FreeFloat: 0},
+		"D": {EarliestStart: 3, EarliestFinish: 8, LatestStart: 3, LatestFinish: 8, TotalFloat: 0, FreeFloat: 0},
+		"E": {EarliestStart: 4, EarliestFinish: 6, LatestStart: 6, LatestFinish: 8, TotalFloat: 2, FreeFloat: 2},
+		"F": {EarliestStart: 8, EarliestFinish: 11, LatestStart: 8, LatestFinish: 11, TotalFloat: 0, FreeFloat: 0},
+	}
+	if len(result.TaskSchedules) != len(want) {
+		t.Fatalf("%d schedule(s), want %d", len(result.TaskSchedules), len(want))
+	}
+	for _, ts := range result.TaskSchedules {
+		w := want[ts.TaskID]
+		w.TaskID, w.WorkerID = ts.TaskID, ts.WorkerID
+		if ts != w {
+			t.Errorf("%s: got %+v, want %+v", ts.TaskID, ts, w)
+		}
+	}
+}
+
+// With limited workers the floats are measured against the simulated plan.
+func TestBackwardPassLimited(t *testing.T) {
+	result, err := NewWorkerScheduler().Schedule(caseStudy(t), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ts := range result.TaskSchedules {
+		if ts.LatestFinish > result.MinCompletionTime {
+			t.Errorf("%s: latest finish %d after completion time %d", ts.TaskID, ts.LatestFinish, result.MinCompletionTime)
+		}
+		if ts.TotalFloat < 0 || ts.FreeFloat < 0 {
+			t.Errorf("%s: negative float in %+v", ts.TaskID, ts)
+		}
+		if ts.LatestStart != ts.LatestFinish-(ts.EarliestFinish-ts.EarliestStart) {
+			t.Errorf("%s: latest start %d does not match latest finish %d", ts.TaskID, ts.LatestStart, ts.LatestFinish)
+		}
+	}
+}
+
+func TestCriticalPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		specs     []spec
+		wantPaths [][]string
+		wantTasks []string
+	}{
+		{
+			name:      "case study",
+			specs:     []spec{{id: "A", dur: 3}, {id: "B", dur: 2}, {id: "C", dur: 4}, {id: "D", dur: 5, deps: []string{"A"}}, {id: "E", dur: 2, deps: []string{"B", "C"}}, {id: "F", dur: 3, deps: []string{"D", "E"}}},
+			wantPaths: [][]string{{"A", "D", "F"}},
+			wantTasks: []string{"A", "D", "F"},
+		},
+		{
+			name:      "diamond",
+			specs:     []spec{{id: "S", dur: 1}, {id: "Y", dur: 2, deps: []string{"S"}}, {id: "X", dur: 2, deps: []string{"S"}}, {id: "T", dur: 1, deps: []string{"X", "Y"}}},
+			wantPaths: [][]string{{"S", "X", "T"}, {"S", "Y", "T"}},
+			wantTasks: []string{"S", "T", "X", "Y"},
+		},
+		{
+			name:      "independent ties",
+			specs:     []spec{{id: "B", dur: 4}, {id: "A", dur: 4}, {id: "C", dur: 1}},
+			wantPaths: [][]string{{"A"}, {"B"}},
+			wantTasks: []string{"A", "B"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := buildJob(t, tt.specs...)
+			result, err := NewWorkerScheduler().Schedule(job, job.TaskCount())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(result.CriticalPaths) != fmt.Sprint(tt.wantPaths) {
+				t.Errorf("critical paths %v, want %v", result.CriticalPaths, tt.wantPaths)
+			}
+			if fmt.Sprint(result.CriticalPath) != fmt.Sprint(tt.wantPaths[0]) {
+				t.Errorf("critical path %v, want %v", result.CriticalPath, tt.wantPaths[0])
+			}
+			if fmt.Sprint(result.CriticalTasks) != fmt.Sprint(tt.wantTasks) {
+				t.Errorf("critical tasks %v, want %v", result.CriticalTasks, tt.wantTasks)
+			}
+		})
+	}
+}
+
+// Every declared worker gets a timeline, also when it stays idle, and
+// tasks that never ran are on none.
+func TestWorkerTimelinesPool(t *testing.T) {
+	job := buildJob(t, spec{id: "a", dur: 2})
+	job.Pool = identicalWorkers(3)
+	result, err := NewWorkerScheduler().Schedule(job, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.WorkerTimelines) != 3 {
+		t.Fatalf("%d timeline(s), want one per declared worker", len(result.WorkerTimelines))
+	}
+	busy := 0
+	for i, wt := range result.WorkerTimelines {
+		if wt.WorkerID != i+1 {
+			t.Errorf("timeline %d is for worker %d", i, wt.WorkerID)
+		}
+		busy += len(wt.Tasks)
+	}
+	if busy != 1 {
+		t.Errorf("%d task(s) on timelines, want 1", busy)
+	}
+
+	skipped := buildWorkerTimelines([]model.TaskSchedule{
+		{TaskID: "a", WorkerID: 2, EarliestFinish: 1},
+		{TaskID: "b"},
+	}, nil)
+	if len(skipped) != 1 || skipped[0].WorkerID != 2 {
+		t.Errorf("timelines %+v, want one for worker 2", skipped)
+	}
+}
+
+// Seven layers of two tasks, each depending on both tasks of the layer
+// before, have 2^7 critical paths; only the first MaxCriticalPaths are kept.
+func TestCriticalPathsTruncated(t *testing.T) {
+	var specs []spec
+	for layer := 0; layer < 7; layer++ {
+		var deps []string
+		if layer > 0 {
+			deps = []string{fmt.Sprintf("a%d", layer-1), fmt.Sprintf("b%d", layer-1)}
+		}
+		specs = append(specs,
+			spec{id: fmt.Sprintf("a%d", layer), dur: 1, deps: deps},
+			spec{id: fmt.Sprintf("b%d", layer), dur: 1, deps: deps})
+	}
+	result, err := NewWorkerScheduler().Schedule(buildJob(t, specs...), len(specs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.CriticalPaths) != MaxCriticalPaths || !result.CriticalPathsTruncated {
+		t.Errorf("%d path(s), truncated %v, want %d and true",
+			len(result.CriticalPaths), result.CriticalPathsTruncated, MaxCriticalPaths)
+	}
+
+	result, err = NewWorkerScheduler().Schedule(caseStudy(t), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CriticalPathsTruncated {
+		t.Error("the case study's single path is reported as truncated")
+	}
+}
+
+func TestWorkerTimelines(t *testing.T) {
+	job := caseStudy(t)
+	for _, workers := range []int{1, 2, 6} {
+		result, err := NewWorkerScheduler().Schedule(job, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[string]bool)
+		for _, wt := range result.WorkerTimelines {
+			if wt.WorkerID < 1 || wt.WorkerID > workers {
+				t.Errorf("%d worker(s): timeline for worker %d", workers, wt.WorkerID)
+			}
+			for i, ts := range wt.Tasks {
+				seen[ts.TaskID] = true
+				if ts.WorkerID != wt.WorkerID {
+					t.Errorf("%d worker(s): %s on timeline %d has worker %d", workers, ts.TaskID, wt.WorkerID, ts.WorkerID)
+				}
+				if i > 0 && ts.EarliestStart < wt.Tasks[i-1].EarliestFinish {
+					t.Errorf("%d worker(s): %s overlaps %s on worker %d", workers, ts.TaskID, wt.Tasks[i-1].TaskID, wt.WorkerID)
+				}
+			}
+		}
+		if len(seen) != job.TaskCount() {
+			t.Errorf("%d worker(s): %d task(s) on timelines, want %d", workers, len(seen), job.TaskCount())
+		}
+	}
+
+	result, err := NewWorkerScheduler().Schedule(job, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"W1: A D F busy 11", "W2: B C E busy 8"}
+	if len(result.WorkerTimelines) != len(want) {
+		t.Fatalf("%d timeline(s), want %d", len(result.WorkerTimelines), len(want))
+	}
+	for i, wt := range result.WorkerTimelines {
+		got := fmt.Sprintf("W%d:", wt.WorkerID)
+		for _, ts := range wt.Tasks {
+			got += " " + ts.TaskID
+		}
+		got += fmt.Sprintf(" busy %d", wt.BusyTime())
+		if got != want[i] {
+			t.Errorf("timeline %d: %q, want %q", i, got, want[i])
+		}
+	}
+}
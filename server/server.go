@@ -0,0 +1,270 @@
+// Package server exposes the scheduler as a JSON HTTP API.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wingie_case/input"
+	"wingie_case/model"
+	"wingie_case/output"
+	"wingie_case/scheduler"
+	"wingie_case/validator"
+)
+
+// DefaultMaxBodyBytes limits the size of a job document.
+const DefaultMaxBodyBytes = 1 << 20
+
+// DefaultMaxLintTasks is the largest job /v1/validate lints; linting needs
+// memory quadratic in the number of tasks. Larger jobs are still validated.
+const DefaultMaxLintTasks = 2000
+
+// DefaultRequestTimeout limits how long scheduling may take; slower requests
+// get a 503 ErrorDocument.
+const DefaultRequestTimeout = 30 * time.Second
+
+// Server handles the HTTP API:
+//
+//	POST /v1/schedule  job document → output.ResultDocument
+//	POST /v1/validate  job document → ValidateDocument
+//	GET  /healthz      → {"status": "ok"}
+//
+// Job documents use the JSON job file schema. The optional "workers" query
+// parameter overrides the document's worker count. Failures are returned as
+// an ErrorDocument: 400 for malformed requests, 422 for invalid jobs and 500
+// when scheduling fails. Jobs above DefaultMaxLintTasks are validated but
+// not linted. A scheduler implementing scheduler.ContextScheduler is
+// stopped after DefaultRequestTimeout, or when the client goes away, and
+// the request gets a 503. Server is safe for concurrent use as long as the
+// validator and scheduler are; the built-in ones are.
+type Server struct {
+	validator    validator.Validator
+	scheduler    scheduler.Scheduler
+	maxBodyBytes int64
+	maxLintTasks int
+	timeout      time.Duration
+	mux          *http.ServeMux
+}
+
+// NewServer creates a Server that checks jobs with val and schedules them with sched.
+func NewServer(val validator.Validator, sched scheduler.Scheduler) *Server {
+	s := &Server{
+		validator:    val,
+		scheduler:    sched,
+		maxBodyBytes: DefaultMaxBodyBytes,
+		maxLintTasks: DefaultMaxLintTasks,
+		timeout:      DefaultRequestTimeout,
+		mux:          http.NewServeMux(),
+	}
+	s.mux.HandleFunc("POST /v1/schedule", s.handleSchedule)
+	s.mux.HandleFunc("POST /v1/validate", s.handleValidate)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+	s.mux.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ValidateDocument is the response of a successful /v1/validate call.
+// LintSkipped is set when the job was too large to lint; Warnings is then
+// empty.
+type ValidateDocument struct {
+	Valid       bool              `json:"valid"`
+	Job         string            `json:"job"`
+	Tasks       int               `json:"tasks"`
+	Workers     int               `json:"workers"`
+	Warnings    []WarningDocument `json:"warnings"`
+	LintSkipped bool              `json:"lint_skipped,omitempty"`
+}
+
+// WarningDocument is the machine-readable form of a validator.Warning.
+type WarningDocument struct {
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ErrorDocument is the body of every failed request.
+type ErrorDocument struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failure. Kind is "request", "input", "validation"
+// or "scheduling"; Problems lists each validation problem.
+type ErrorDetail struct {
+	Kind     string            `json:"kind"`
+	Message  string            `json:"message"`
+	Problems []ProblemDocument `json:"problems,omitempty"`
+}
+
+// ProblemDocument is one validation problem. Cycle problems list the
+// concrete cycles and the tasks they block.
+type ProblemDocument struct {
+	Field     string     `json:"field,omitempty"`
+	Message   string     `json:"message"`
+	Cycles    [][]string `json:"cycles,omitempty"`
+	Unvisited []string   `json:"unvisited,omitempty"`
+}
+
+func (s *Server) handleSchedule(w http.ResponseWriter, r *http.Request) {
+	in, ok := s.readJob(w, r)
+	if !ok {
+		return
+	}
+	result, err := s.schedule(r.Context(), in)
+	if errors.Is(err, context.DeadlineExceeded) {
+		writeError(w, http.StatusServiceUnavailable, ErrorDetail{
+			Kind:    "request",
+			Message: fmt.Sprintf("request took longer than %s", s.timeout),
+		})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrorDetail{Kind: "scheduling", Message: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, output.NewResultDocument(result))
+}
+
+// schedule runs the scheduler, within ctx if it supports that.
+func (s *Server) schedule(ctx context.Context, in *input.JobInput) (*model.ScheduleResult, error) {
+	if sched, ok := s.scheduler.(scheduler.ContextScheduler); ok {
+		return sched.ScheduleContext(ctx, in.Job, in.Workers)
+	}
+	return s.scheduler.Schedule(in.Job, in.Workers)
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	in, ok := s.readJob(w, r)
+	if !ok {
+		return
+	}
+	doc := ValidateDocument{
+		Valid:    true,
+		Job:      in.Job.Name,
+		Tasks:    in.Job.TaskCount(),
+		Workers:  in.Workers,
+		Warnings: []WarningDocument{},
+	}
+	linter, canLint := s.validator.(validator.Linter)
+	switch {
+	case !canLint:
+	case in.Job.TaskCount() > s.maxLintTasks:
+		doc.LintSkipped = true
+	default:
+		for _, warning := range linter.Lint(in.Job) {
+			doc.Warnings = append(doc.Warnings, WarningDocument{
+				Code:    warning.Code,
+				Field:   warning.Field,
+				Message: warning.Message,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readJob decodes and validates the job in the request body, applying the
+// workers query parameter. On failure it writes the error response and
+// returns false.
+func (s *Server) readJob(w http.ResponseWriter, r *http.Request) (*input.JobInput, bool) {
+	workers := 0
+	if raw := r.URL.Query().Get("workers"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, ErrorDetail{
+				Kind:    "request",
+				Message: fmt.Sprintf("workers must be a positive integer, got '%s'", raw),
+			})
+			return nil, false
+		}
+		workers = n
+	}
+
+	body := http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+	in, err := input.NewJSONReader(body).ReadJob()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrorDetail{
+				Kind:    "request",
+				Message: fmt.Sprintf("job document exceeds %d bytes", tooLarge.Limit),
+			})
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, ErrorDetail{Kind: "input", Message: err.Error()})
+		return nil, false
+	}
+	if workers > 0 {
+		if n := len(in.Job.Pool); n > 0 && workers != n {
+			writeError(w, http.StatusBadRequest, ErrorDetail{
+				Kind:    "request",
+				Message: fmt.Sprintf("workers: the job declares a pool of %d worker(s)", n),
+			})
+			return nil, false
+		}
+		in.Workers = workers
+	}
+
+	if err := s.validator.Validate(in.Job); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, ErrorDetail{
+			Kind:     "validation",
+			Message:  err.Error(),
+			Problems: problems(err),
+		})
+		return nil, false
+	}
+	return in, true
+}
+
+// problems lists the individual validation problems in err.
+func problems(err error) []ProblemDocument {
+	errs := []error{err}
+	var multi *validator.ValidationErrors
+	if errors.As(err, &multi) {
+		errs = multi.Errors
+	}
+
+	docs := make([]ProblemDocument, 0, len(errs))
+	for _, e := range errs {
+		var fieldErr *validator.ValidationError
+		var cycleErr *validator.CycleError
+		switch {
+		case errors.As(e, &fieldErr):
+			docs = append(docs, ProblemDocument{Field: fieldErr.Field, Message: fieldErr.Message})
+		case errors.As(e, &cycleErr):
+			docs = append(docs, ProblemDocument{
+				Message:   cycleErr.Message,
+				Cycles:    cycleErr.Cycles,
+				Unvisited: cycleErr.Unvisited,
+			})
+		default:
+			docs = append(docs, ProblemDocument{Message: e.Error()})
+		}
+	}
+	return docs
+}
+
+func writeError(w http.ResponseWriter, status int, detail ErrorDetail) {
+	writeJSON(w, status, ErrorDocument{Error: detail})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wingie_case/scheduler"
+	"wingie_case/validator"
+)
+
+const caseStudy = `{
+  "name": "J",
+  "workers": 2,
+  "tasks": [
+    {"id": "A", "duration": 3},
+    {"id": "B", "duration": 2},
+    {"id": "C", "duration": 4},
+    {"id": "D", "duration": 5, "dependencies": ["A"]},
+    {"id": "E", "duration": 2, "dependencies": ["B", "C"]},
+    {"id": "F", "duration": 3, "dependencies": ["D", "E"]}
+  ]
+}`
+
+func newTestServer() *Server {
+	return NewServer(validator.NewGraphValidator(), scheduler.NewWorkerScheduler())
+}
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{name: "health", method: "GET", path: "/healthz", status: http.StatusOK, want: `"status": "ok"`},
+		{name: "schedule", method: "POST", path: "/v1/schedule", body: caseStudy, status: http.StatusOK, want: `"min_completion_time": 11`},
+		{name: "more workers", method: "POST", path: "/v1/schedule?workers=6", body: caseStudy, status: http.StatusOK, want: `"workers": 6`},
+		{name: "bad workers", method: "POST", path: "/v1/schedule?workers=0", body: caseStudy, status: http.StatusBadRequest, want: `"kind": "request"`},
+		{name: "bad document", method: "POST", path: "/v1/schedule", body: `{"tasks": [`, status: http.StatusBadRequest, want: `"kind": "input"`},
+		{
+			name:   "cycle",
+			method: "POST",
+			path:   "/v1/validate",
+			body:   `{"tasks": [{"id": "A", "duration": 1, "dependencies": ["B"]}, {"id": "B", "duration": 1, "dependencies": ["A"]}]}`,
+			status: http.StatusUnprocessableEntity,
+			want:   `"cycles"`,
+		},
+		{name: "validate", method: "POST", path: "/v1/validate", body: caseStudy, status: http.StatusOK, want: `"valid": true`},
+		{
+			name:   "lint warnings",
+			method: "POST",
+			path:   "/v1/validate",
+			body:   `{"tasks": [{"id": "A", "duration": 1}, {"id": "B", "duration": 1, "dependencies": ["A"]}, {"id": "C", "duration": 1, "dependencies": ["A", "B"]}]}`,
+			status: http.StatusOK,
+			want:   `"code": "redundant-dependency"`,
+		},
+		{name: "wrong method", method: "GET", path: "/v1/schedule", status: http.StatusMethodNotAllowed},
+	}
+	srv := newTestServer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("body lacks %q: %s", tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidateSkipsLintForLargeJobs(t *testing.T) {
+	srv := newTestServer()
+	for _, limit := range []int{100, 3} {
+		srv.maxLintTasks = limit
+		req := httptest.NewRequest("POST", "/v1/validate", strings.NewReader(caseStudy))
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		var doc ValidateDocument
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("limit %d: %v: %s", limit, err, rec.Body.String())
+		}
+		if want := limit < doc.Tasks; doc.LintSkipped != want {
+			t.Errorf("limit %d: lint skipped %v, want %v", limit, doc.LintSkipped, want)
+		}
+	}
+}
+
+// Scheduling is stopped when the request runs out of time, and the client
+// gets a JSON error.
+func TestScheduleTimeout(t *testing.T) {
+	srv := newTestServer()
+	srv.timeout = time.Nanosecond
+	req := httptest.NewRequest("POST", "/v1/schedule", strings.NewReader(caseStudy))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("content type %q, want application/json", got)
+	}
+	var doc ErrorDocument
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("%v: %s", err, rec.Body.String())
+	}
+	if doc.Error.Kind != "request" || !strings.Contains(doc.Error.Message, "took longer than 1ns") {
+		t.Errorf("error %+v, want a request timeout", doc.Error)
+	}
+}
@@ -0,0 +1,13 @@
+package validator
+
+import "wingie_case/model"
+
+// CycleError is returned when the dependency graph contains a cycle; see
+// model.CycleError.
+type CycleError = model.CycleError
+
+// DetectCycle returns the cycles of the job's dependency graph, or nil for a
+// DAG. It is model.Job.DetectCycle.
+func DetectCycle(job *model.Job) *CycleError {
+	return job.DetectCycle()
+}
@@ -0,0 +1,185 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"wingie_case/model"
+)
+
+// Warning codes reported by GraphValidator.Lint.
+const (
+	WarnRedundantDependency = "redundant-dependency"
+	WarnIsolatedTask        = "isolated-task"
+	WarnDisconnected        = "disconnected-components"
+)
+
+// DefaultIsolatedTaskThreshold is the smallest job for which tasks without
+// any dependency links are reported. In small jobs an independent task is
+// usually intentional.
+const DefaultIsolatedTaskThreshold = 10
+
+// Warning is a problem that does not prevent scheduling but suggests the job
+// definition could be cleaner.
+type Warning struct {
+	Code    string
+	Field   string
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Field != "" {
+		return fmt.Sprintf("[%s] %s", w.Field, w.Message)
+	}
+	return w.Message
+}
+
+// Linter is implemented by validators that can report warnings. Lint is
+// meant to run on a job that passed Validate; on an invalid job it ignores
+// the broken links instead of failing.
+type Linter interface {
+	Lint(job *model.Job) []Warning
+}
+
+// Lint reports, in this order:
+//   - dependencies already implied by another dependency of the same task
+//     (C depends on A and B, and B already depends on A); typed links are
+//     only implied through finish-to-start links (see
+//     model.Job.FinishedAncestors) and never reported themselves;
+//   - tasks with no dependencies and no dependents, in jobs of at least
+//     the isolated-task threshold;
+//   - jobs whose tasks split into several groups with no links between them,
+//     not counting the tasks already reported as isolated.
+//
+// Tasks are visited in ID order so the report is the same on every run.
+func (v *GraphValidator) Lint(job *model.Job) []Warning {
+	ids := sortedTaskIDs(job)
+	deps := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		deps[id] = job.Predecessors(id)
+	}
+	ancestors := job.FinishedAncestorSets()
+
+	var warnings []Warning
+	for _, id := range ids {
+		task := job.Tasks[id]
+		var sequential []string // dependencies that finish before the task starts
+		for _, depID := range deps[id] {
+			if task.Link(depID).FinishesFirst() {
+				sequential = append(sequential, depID)
+			}
+		}
+		for _, depID := range deps[id] {
+			if !task.Link(depID).IsPlain() {
+				continue
+			}
+			if via := impliedBy(depID, sequential, ancestors); via != "" {
+				warnings = append(warnings, Warning{
+					Code:  WarnRedundantDependency,
+					Field: fmt.Sprintf("task.%s.dependencies", id),
+					Message: fmt.Sprintf("dependency '%s' of task '%s' is redundant: already implied through '%s'",
+						depID, id, via),
+				})
+			}
+		}
+	}
+
+	linked := make(map[string]bool, len(ids))
+	for id, list := range deps {
+		if len(list) > 0 {
+			linked[id] = true
+		}
+		for _, depID := range list {
+			linked[depID] = true
+		}
+	}
+	// Tasks reported as isolated are left out of the groups below, so that
+	// each is only warned about once.
+	grouped := ids
+	if len(ids) >= v.isolatedThreshold {
+		grouped = make([]string, 0, len(ids))
+		for _, id := range ids {
+			if linked[id] {
+				grouped = append(grouped, id)
+				continue
+			}
+			warnings = append(warnings, Warning{
+				Code:    WarnIsolatedTask,
+				Field:   fmt.Sprintf("task.%s", id),
+				Message: fmt.Sprintf("task '%s' has no dependencies and nothing depends on it", id),
+			})
+		}
+	}
+
+	if groups := components(grouped, deps); len(groups) > 1 {
+		names := make([]string, len(groups))
+		for i, group := range groups {
+			names[i] = describeGroup(group)
+		}
+		warnings = append(warnings, Warning{
+			Code:  WarnDisconnected,
+			Field: "job.tasks",
+			Message: fmt.Sprintf("job splits into %d independent groups of tasks: %s",
+				len(groups), strings.Join(names, ", ")),
+		})
+	}
+	return warnings
+}
+
+// impliedBy returns the first other dependency (in list order) that already
+// depends on depID, or "" when the link is needed.
+func impliedBy(depID string, siblings []string, ancestors *model.Reachability) string {
+	for _, other := range siblings {
+		if other != depID && ancestors.Contains(other, depID) && !ancestors.Contains(depID, other) {
+			return other
+		}
+	}
+	return ""
+}
+
+// components groups the tasks connected by dependency links in either
+// direction. Groups are sorted internally and by their first ID.
+func components(ids []string, deps map[string][]string) [][]string {
+	neighbours := make(map[string][]string, len(ids))
+	for id, list := range deps {
+		for _, depID := range list {
+			neighbours[id] = append(neighbours[id], depID)
+			neighbours[depID] = append(neighbours[depID], id)
+		}
+	}
+
+	seen := make(map[string]bool, len(ids))
+	var groups [][]string
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		var group []string
+		stack := []string{id}
+		seen[id] = true
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			group = append(group, current)
+			for _, next := range neighbours[current] {
+				if !seen[next] {
+					seen[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		sort.Strings(group)
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// describeGroup lists up to three task IDs of a group, e.g. {A, B, C, +2 more}.
+func describeGroup(group []string) string {
+	const shown = 3
+	if len(group) <= shown {
+		return "{" + strings.Join(group, ", ") + "}"
+	}
+	return fmt.Sprintf("{%s, +%d more}", strings.Join(group[:shown], ", "), len(group)-shown)
+}
@@ -3,6 +3,8 @@ package validator
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"wingie_case/model"
 )
@@ -20,13 +22,28 @@ func (e *ValidationError) Error() string {
 	return e.Message
 }
 
-// CycleError is returned when the dependency graph contains a cycle.
-type CycleError struct {
-	Message string
+// ValidationErrors collects every problem found in a job. Errors are
+// *ValidationError values ordered by task ID, followed by a *CycleError when
+// the graph is cyclic. errors.Is and errors.As look through all of them.
+type ValidationErrors struct {
+	Errors []error
 }
 
-func (e *CycleError) Error() string {
-	return e.Message
+func (e *ValidationErrors) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("found %d problems:", len(e.Errors)))
+	for _, err := range e.Errors {
+		lines = append(lines, "  - "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap exposes the individual errors to errors.Is and errors.As.
+func (e *ValidationErrors) Unwrap() []error {
+	return e.Errors
 }
 
 // Validator defines the contract for job validation.
@@ -35,94 +52,212 @@ type Validator interface {
 }
 
 // GraphValidator validates the dependency graph of a job.
-// It checks for empty jobs, invalid durations, undefined or self
-// dependencies, and cycles.
-type GraphValidator struct{}
+// It checks for empty jobs, invalid durations, undefined, duplicate or self
+// dependencies, malformed links, tasks no worker can run, resource demands that exceed the
+// capacities, and cycles. Lint reports softer problems as warnings.
+type GraphValidator struct {
+	isolatedThreshold int
+}
 
 func NewGraphValidator() *GraphValidator {
-	return &GraphValidator{}
+	return NewGraphValidatorWithThreshold(DefaultIsolatedTaskThreshold)
+}
+
+// NewGraphValidatorWithThreshold creates a GraphValidator that reports
+// isolated tasks only in jobs with at least minTasks tasks. Non-positive
+// values select DefaultIsolatedTaskThreshold.
+func NewGraphValidatorWithThreshold(minTasks int) *GraphValidator {
+	if minTasks <= 0 {
+		minTasks = DefaultIsolatedTaskThreshold
+	}
+	return &GraphValidator{isolatedThreshold: minTasks}
 }
 
-// Validate runs all checks and returns the first error encountered.
+// Validate runs all checks and returns every problem found as a
+// *ValidationErrors, or nil when the job is valid. Tasks are checked in ID
+// order so the report is the same on every run.
 func (v *GraphValidator) Validate(job *model.Job) error {
 	if job.TaskCount() == 0 {
-		return &ValidationError{
+		return &ValidationErrors{Errors: []error{&ValidationError{
 			Field:   "job.tasks",
 			Message: "job has no tasks",
-		}
+		}}}
 	}
 
-	for id, task := range job.Tasks {
+	var errs []error
+	for _, id := range sortedTaskIDs(job) {
+		task := job.Tasks[id]
 		if task.Duration <= 0 {
-			return &ValidationError{
+			errs = append(errs, &ValidationError{
 				Field:   fmt.Sprintf("task.%s.duration", id),
 				Message: fmt.Sprintf("duration must be positive, got %d", task.Duration),
-			}
+			})
 		}
 
+		seen := make(map[string]bool, len(task.Dependencies))
 		for _, depID := range task.Dependencies {
-			if depID == id {
-				return &ValidationError{
-					Field:   fmt.Sprintf("task.%s.dependencies", id),
+			field := fmt.Sprintf("task.%s.dependencies", id)
+			switch {
+			case depID == id:
+				errs = append(errs, &ValidationError{
+					Field:   field,
 					Message: fmt.Sprintf("task '%s' cannot depend on itself", id),
+				})
+			case seen[depID]:
+				errs = append(errs, &ValidationError{
+					Field:   field,
+					Message: fmt.Sprintf("task '%s' lists dependency '%s' more than once", id, depID),
+				})
+			default:
+				if _, exists := job.Tasks[depID]; !exists {
+					errs = append(errs, &ValidationError{
+						Field:   field,
+						Message: fmt.Sprintf("task '%s' depends on undefined task '%s'", id, depID),
+					})
 				}
 			}
-			if _, exists := job.Tasks[depID]; !exists {
-				return &ValidationError{
-					Field:   fmt.Sprintf("task.%s.dependencies", id),
-					Message: fmt.Sprintf("task '%s' depends on undefined task '%s'", id, depID),
-				}
-			}
+			seen[depID] = true
+		}
+
+		errs = append(errs, validateLinks(task)...)
+		if err := validateRequires(task, job.Pool); err != nil {
+			errs = append(errs, err)
 		}
+		errs = append(errs, validateDemands(task, job.Resources)...)
 	}
 
-	return v.detectCycle(job)
+	errs = append(errs, validatePool(job.Pool)...)
+	errs = append(errs, validateCapacities(job.Resources)...)
+	if err := job.DetectCycle(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return &ValidationErrors{Errors: errs}
+	}
+	return nil
 }
 
-// detectCycle uses Kahn's algorithm (BFS topological sort) to detect cycles.
-// If not all nodes are visited, the graph contains a cycle.
-// Time complexity: O(V + E)
-func (v *GraphValidator) detectCycle(job *model.Job) error {
-	indegree := make(map[string]int, job.TaskCount())
-	adjacency := make(map[string][]string, job.TaskCount())
+// validatePool checks that workers are numbered 1..n in order and have
+// positive speeds.
+func validatePool(pool []model.Worker) []error {
+	var errs []error
+	for i, w := range pool {
+		if w.ID != i+1 {
+			errs = append(errs, &ValidationError{
+				Field:   fmt.Sprintf("pool[%d].id", i),
+				Message: fmt.Sprintf("worker '%s' must have ID %d, got %d", w.Name, i+1, w.ID),
+			})
+		}
+		if !(w.Speed > 0) {
+			errs = append(errs, &ValidationError{
+				Field:   fmt.Sprintf("pool[%d].speed", i),
+				Message: fmt.Sprintf("speed of worker '%s' must be positive, got %g", w.Name, w.Speed),
+			})
+		}
+	}
+	return errs
+}
 
-	for id := range job.Tasks {
-		indegree[id] = 0
+// validateLinks checks that typed links have a known type and belong to one
+// of the task's dependencies.
+func validateLinks(task *model.Task) []error {
+	depIDs := make([]string, 0, len(task.Links))
+	for depID := range task.Links {
+		depIDs = append(depIDs, depID)
 	}
+	sort.Strings(depIDs)
 
-	for id, task := range job.Tasks {
-		for _, depID := range task.Dependencies {
-			adjacency[depID] = append(adjacency[depID], id)
-			indegree[id]++
+	var errs []error
+	field := fmt.Sprintf("task.%s.dependencies", task.ID)
+	for _, depID := range depIDs {
+		link := task.Links[depID]
+		switch {
+		case !task.DependsOn(depID):
+			errs = append(errs, &ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("task '%s' has a link to '%s', which is not one of its dependencies", task.ID, depID),
+			})
+		case link.Type != "" && !link.Type.Valid():
+			errs = append(errs, &ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("task '%s' has an unknown dependency type '%s' on '%s'", task.ID, link.Type, depID),
+			})
 		}
 	}
+	return errs
+}
 
-	queue := make([]string, 0, job.TaskCount())
-	for id, deg := range indegree {
-		if deg == 0 {
-			queue = append(queue, id)
+// validateRequires reports a task whose required tags no worker of the pool
+// has all of.
+func validateRequires(task *model.Task, pool []model.Worker) error {
+	if len(task.Requires) == 0 {
+		return nil
+	}
+	for _, w := range pool {
+		if w.CanRun(task) {
+			return nil
 		}
 	}
+	message := fmt.Sprintf("no worker can run task '%s': it requires %s", task.ID, strings.Join(task.Requires, ", "))
+	if len(pool) == 0 {
+		message += " but the job declares no worker pool"
+	}
+	return &ValidationError{Field: fmt.Sprintf("task.%s.requires", task.ID), Message: message}
+}
 
-	visitedCount := 0
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		visitedCount++
-
-		for _, neighbor := range adjacency[current] {
-			indegree[neighbor]--
-			if indegree[neighbor] == 0 {
-				queue = append(queue, neighbor)
-			}
+// validateDemands checks that every resource the task demands is declared
+// by the job, and that the demand is positive and within the capacity.
+func validateDemands(task *model.Task, capacities map[string]int) []error {
+	var errs []error
+	field := fmt.Sprintf("task.%s.resources", task.ID)
+	for _, name := range sortedKeys(task.Resources) {
+		demand := task.Resources[name]
+		capacity, declared := capacities[name]
+		var message string
+		switch {
+		case demand <= 0:
+			message = fmt.Sprintf("task '%s' must demand a positive amount of resource '%s', got %d", task.ID, name, demand)
+		case !declared:
+			message = fmt.Sprintf("task '%s' demands undeclared resource '%s'", task.ID, name)
+		case demand > capacity:
+			message = fmt.Sprintf("task '%s' demands %d of resource '%s', which has a capacity of %d",
+				task.ID, demand, name, capacity)
+		default:
+			continue
 		}
+		errs = append(errs, &ValidationError{Field: field, Message: message})
 	}
+	return errs
+}
 
-	if visitedCount != job.TaskCount() {
-		return &CycleError{
-			Message: "dependency graph contains a cycle, job cannot be completed",
+// validateCapacities checks that every resource has a positive capacity.
+func validateCapacities(capacities map[string]int) []error {
+	var errs []error
+	for _, name := range sortedKeys(capacities) {
+		if capacities[name] <= 0 {
+			errs = append(errs, &ValidationError{
+				Field:   "resources." + name,
+				Message: fmt.Sprintf("capacity of resource '%s' must be positive, got %d", name, capacities[name]),
+			})
 		}
 	}
+	return errs
+}
 
-	return nil
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedTaskIDs(job *model.Job) []string {
+	ids := make([]string, 0, job.TaskCount())
+	for id := range job.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
 }
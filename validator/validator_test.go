@@ -0,0 +1,244 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"wingie_case/model"
+)
+
+// jobOf builds a job from "id:duration:dep,dep" entries; a dependency
+// written "dep=SS+1" gets that link.
+func jobOf(t *testing.T, entries ...string) *model.Job {
+	t.Helper()
+	job := model.NewJob("J")
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		var duration int
+		fmt.Sscan(parts[1], &duration)
+		task := &model.Task{ID: parts[0], Duration: duration, Dependencies: []string{}}
+		if len(parts) > 2 && parts[2] != "" {
+			for _, dep := range strings.Split(parts[2], ",") {
+				id, notation, typed := strings.Cut(dep, "=")
+				task.Dependencies = append(task.Dependencies, id)
+				if typed {
+					link, err := model.ParseLink(notation)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if task.Links == nil {
+						task.Links = map[string]model.Link{}
+					}
+					task.Links[id] = link
+				}
+			}
+		}
+		if err := job.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return job
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     []string
+		wantErr []string // one substring per expected problem
+	}{
+		{name: "valid", job: []string{"a:1", "b:2:a", "c:1:a,b"}},
+		{name: "empty", job: nil, wantErr: []string{"job has no tasks"}},
+		{name: "duration", job: []string{"a:0"}, wantErr: []string{"duration must be positive"}},
+		{name: "self", job: []string{"a:1:a"}, wantErr: []string{"cannot depend on itself"}},
+		{name: "undefined", job: []string{"a:1:x"}, wantErr: []string{"undefined task 'x'"}},
+		{name: "duplicate", job: []string{"a:1", "b:1:a,a"}, wantErr: []string{"more than once"}},
+		{name: "cycle", job: []string{"a:1:c", "b:1:a", "c:1:b", "d:1:c"}, wantErr: []string{"a -> b -> c -> a"}},
+		{
+			name:    "several",
+			job:     []string{"a:0:x", "b:1:b"},
+			wantErr: []string{"duration must be positive", "undefined task 'x'", "cannot depend on itself"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGraphValidator().Validate(jobOf(t, tt.job...))
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var multi *ValidationErrors
+			if !errors.As(err, &multi) {
+				t.Fatalf("Validate() = %v, want *ValidationErrors", err)
+			}
+			if len(multi.Errors) != len(tt.wantErr) {
+				t.Fatalf("%d problem(s) %v, want %d", len(multi.Errors), multi.Errors, len(tt.wantErr))
+			}
+			for i, want := range tt.wantErr {
+				if !strings.Contains(multi.Errors[i].Error(), want) {
+					t.Errorf("problem %d = %v, want it to contain %q", i, multi.Errors[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePool(t *testing.T) {
+	job := jobOf(t, "a:1")
+	job.Pool = []model.Worker{{ID: 1, Name: "W1", Speed: 1}, {ID: 3, Name: "W3", Speed: 0}}
+	err := NewGraphValidator().Validate(job)
+	var multi *ValidationErrors
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("Validate() = %v, want two problems", err)
+	}
+	for i, want := range []string{"worker 'W3' must have ID 2", "pool[1].speed"} {
+		if !strings.Contains(multi.Errors[i].Error(), want) {
+			t.Errorf("problem %d = %v, want it to contain %q", i, multi.Errors[i], want)
+		}
+	}
+}
+
+func TestValidateRequires(t *testing.T) {
+	job := jobOf(t, "a:1", "b:1")
+	job.Tasks["a"].Requires = []string{"gpu", "ssd"}
+	job.Tasks["b"].Requires = []string{"gpu"}
+	job.Pool = []model.Worker{{ID: 1, Speed: 1, Tags: []string{"gpu"}}, {ID: 2, Speed: 1, Tags: []string{"ssd"}}}
+	err := NewGraphValidator().Validate(job)
+	if err == nil || !strings.Contains(err.Error(), "no worker can run task 'a': it requires gpu, ssd") ||
+		strings.Contains(err.Error(), "task 'b'") {
+		t.Errorf("Validate() = %v, want only task a to be unrunnable", err)
+	}
+
+	job.Pool = nil
+	if err := NewGraphValidator().Validate(job); err == nil || !strings.Contains(err.Error(), "declares no worker pool") {
+		t.Errorf("Validate() = %v, want the missing pool reported", err)
+	}
+}
+
+func TestValidateResources(t *testing.T) {
+	job := jobOf(t, "a:1", "b:1", "c:1")
+	job.Resources = map[string]int{"cpu": 2, "gpu": 0}
+	job.Tasks["a"].Resources = map[string]int{"cpu": 3}
+	job.Tasks["b"].Resources = map[string]int{"disk": 1}
+	job.Tasks["c"].Resources = map[string]int{"cpu": 2}
+	err := NewGraphValidator().Validate(job)
+	var multi *ValidationErrors
+	if !errors.As(err, &multi) {
+		t.Fatalf("Validate() = %v, want *ValidationErrors", err)
+	}
+	want := []string{
+		"task 'a' demands 3 of resource 'cpu', which has a capacity of 2",
+		"task 'b' demands undeclared resource 'disk'",
+		"capacity of resource 'gpu' must be positive, got 0",
+	}
+	if len(multi.Errors) != len(want) {
+		t.Fatalf("%d problem(s) %v, want %d", len(multi.Errors), multi.Errors, len(want))
+	}
+	for i, w := range want {
+		if !strings.Contains(multi.Errors[i].Error(), w) {
+			t.Errorf("problem %d = %v, want it to contain %q", i, multi.Errors[i], w)
+		}
+	}
+}
+
+func TestValidateLinks(t *testing.T) {
+	job := jobOf(t, "a:1", "b:1:a=SS-1", "c:1:a")
+	job.Tasks["c"].Links = map[string]model.Link{"a": {Type: "XX"}, "b": {Type: model.FinishToFinish}}
+	err := NewGraphValidator().Validate(job)
+	var multi *ValidationErrors
+	if !errors.As(err, &multi) {
+		t.Fatalf("Validate() = %v, want *ValidationErrors", err)
+	}
+	want := []string{
+		"task 'c' has an unknown dependency type 'XX' on 'a'",
+		"task 'c' has a link to 'b', which is not one of its dependencies",
+	}
+	if len(multi.Errors) != len(want) {
+		t.Fatalf("%d problem(s) %v, want %d", len(multi.Errors), multi.Errors, len(want))
+	}
+	for i, w := range want {
+		if !strings.Contains(multi.Errors[i].Error(), w) {
+			t.Errorf("problem %d = %v, want it to contain %q", i, multi.Errors[i], w)
+		}
+	}
+}
+
+func TestValidateCycleDetails(t *testing.T) {
+	err := NewGraphValidator().Validate(jobOf(t, "a:1:c", "b:1:a", "c:1:b", "d:1:c", "e:1"))
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Validate() = %v, want a *CycleError", err)
+	}
+	if len(cycleErr.Cycles) != 1 || strings.Join(cycleErr.Cycles[0], ",") != "a,b,c,a" {
+		t.Errorf("cycles %v, want [[a b c a]]", cycleErr.Cycles)
+	}
+	if strings.Join(cycleErr.Unvisited, ",") != "a,b,c,d" {
+		t.Errorf("unvisited %v, want [a b c d]", cycleErr.Unvisited)
+	}
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name      string
+		job       []string
+		threshold int
+		want      []string // "code field"
+	}{
+		{
+			name: "clean",
+			job:  []string{"a:1", "b:1:a", "c:1:b"},
+		},
+		{
+			name: "redundant",
+			job:  []string{"a:1", "b:1:a", "c:1:a,b"},
+			want: []string{"redundant-dependency task.c.dependencies"},
+		},
+		{
+			name: "typed link not redundant",
+			job:  []string{"a:1", "b:1:a", "c:1:a=SS+1,b"},
+		},
+		{
+			name: "implied only through finish-to-start",
+			job:  []string{"a:1", "b:1:a=SS", "c:1:a,b"},
+		},
+		{
+			name: "disconnected",
+			job:  []string{"a:1", "b:1:a", "c:1", "d:1:c"},
+			want: []string{"disconnected-components job.tasks"},
+		},
+		{
+			// Below the threshold the lone task counts as a group.
+			name: "isolated below threshold",
+			job:  []string{"a:1", "b:1:a", "c:1"},
+			want: []string{"disconnected-components job.tasks"},
+		},
+		{
+			// Isolated tasks are reported once, not again as a group.
+			name:      "isolated",
+			job:       []string{"a:1", "b:1:a", "c:1"},
+			threshold: 3,
+			want:      []string{"isolated-task task.c"},
+		},
+		{
+			name:      "isolated and disconnected",
+			job:       []string{"a:1", "b:1:a", "c:1", "d:1", "e:1:d"},
+			threshold: 3,
+			want:      []string{"isolated-task task.c", "disconnected-components job.tasks"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := NewGraphValidatorWithThreshold(tt.threshold).Lint(jobOf(t, tt.job...))
+			got := make([]string, 0, len(warnings))
+			for _, w := range warnings {
+				got = append(got, w.Code+" "+w.Field)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("warnings %q, want %q", got, tt.want)
+			}
+		})
+	}
+}